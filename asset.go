@@ -0,0 +1,156 @@
+package gopenqa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+/* Asset, e.g. an iso, hdd or repo */
+type Asset struct {
+	ID           int       `json:"id"`
+	Type         string    `json:"type"` // e.g. "iso", "hdd", "repo" or "other"
+	Name         string    `json:"name"`
+	Size         FlexInt64 `json:"size"` // Size in bytes, 0 if unknown
+	Checksum     string    `json:"checksum"`
+	Fixed        FlexBool  `json:"fixed"` // Fixed assets are not subject to the cleanup
+	LastUseJobID int64     `json:"last_use_job_id"`
+	Tcreated     string    `json:"t_created"`
+	Tupdated     string    `json:"t_updated"`
+	/* only present in the asset status */
+	Groups  map[int]int64 `json:"groups"`  // ID of the latest job using this asset by job group ID
+	Parents map[int]int64 `json:"parents"` // ID of the latest job using this asset by parent job group ID
+	MaxJob  int64         `json:"max_job"` // ID of the latest job using this asset
+	Pending FlexBool      `json:"pending"` // Asset is used by a job that is not finished yet
+}
+
+/* Asset usage of a job group as computed by the asset cleanup */
+type AssetGroup struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"group"`
+	ParentID    int       `json:"parent_id"`
+	SizeLimitGB FlexInt64 `json:"size_limit_gb"`
+	Size        FlexInt64 `json:"size"`   // Size of all assets of the group in bytes
+	Picked      FlexInt64 `json:"picked"` // Size of the assets that are kept for this group in bytes
+}
+
+/* Asset status as shown on the assets admin page. Contains all assets including the groups and jobs that reference them */
+type AssetsStatus struct {
+	Assets []Asset            `json:"data"`
+	Groups map[int]AssetGroup `json:"groups"`
+}
+
+/* Get all assets */
+func (i *Instance) GetAssets() ([]Asset, error) {
+	return i.GetAssetsContext(context.Background())
+}
+
+func (i *Instance) GetAssetsContext(ctx context.Context) ([]Asset, error) {
+	url := fmt.Sprintf("%s/api/v1/assets", i.URL)
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return make([]Asset, 0), err
+	}
+	// assets come in a "assets:[...]" dict
+	assets := make(map[string][]Asset, 0)
+	if err := json.Unmarshal(resp, &assets); err != nil {
+		return make([]Asset, 0), err
+	}
+	if assets, ok := assets["assets"]; ok {
+		return assets, nil
+	}
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(resp))
+	}
+	return make([]Asset, 0), ErrInvalidResponse
+}
+
+/* Get a single asset by its ID */
+func (i *Instance) GetAsset(id int) (Asset, error) {
+	return i.GetAssetContext(context.Background(), id)
+}
+
+func (i *Instance) GetAssetContext(ctx context.Context, id int) (Asset, error) {
+	url := fmt.Sprintf("%s/api/v1/assets/%d", i.URL, id)
+	return i.fetchAsset(ctx, url)
+}
+
+/* Get a single asset by its type and name */
+func (i *Instance) GetAssetByName(assetType string, name string) (Asset, error) {
+	return i.GetAssetByNameContext(context.Background(), assetType, name)
+}
+
+func (i *Instance) GetAssetByNameContext(ctx context.Context, assetType string, name string) (Asset, error) {
+	rurl := fmt.Sprintf("%s/api/v1/assets/%s/%s", i.URL, url.PathEscape(assetType), url.PathEscape(name))
+	return i.fetchAsset(ctx, rurl)
+}
+
+func (i *Instance) fetchAsset(ctx context.Context, url string) (Asset, error) {
+	var asset Asset
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return asset, err
+	}
+	err = json.Unmarshal(resp, &asset)
+	return asset, err
+}
+
+/* Delete the asset with the given ID */
+func (i *Instance) DeleteAsset(id int) error {
+	return i.DeleteAssetContext(context.Background(), id)
+}
+
+func (i *Instance) DeleteAssetContext(ctx context.Context, id int) error {
+	return i.deleteAsset(ctx, fmt.Sprintf("%s/api/v1/assets/%d", i.URL, id))
+}
+
+/* Delete the asset with the given type and name */
+func (i *Instance) DeleteAssetByName(assetType string, name string) error {
+	return i.DeleteAssetByNameContext(context.Background(), assetType, name)
+}
+
+func (i *Instance) DeleteAssetByNameContext(ctx context.Context, assetType string, name string) error {
+	return i.deleteAsset(ctx, fmt.Sprintf("%s/api/v1/assets/%s/%s", i.URL, url.PathEscape(assetType), url.PathEscape(name)))
+}
+
+func (i *Instance) deleteAsset(ctx context.Context, rurl string) error {
+	if i.apikey == "" || i.apisecret == "" {
+		return fmt.Errorf("API key or secret not set")
+	}
+	buf, err := i.delete(ctx, rurl, nil)
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	return err
+}
+
+/* Get the asset status, i.e. all assets including the job groups and jobs that reference them. Requires SetSessionCookie */
+func (i *Instance) GetAssetsStatus() (AssetsStatus, error) {
+	return i.GetAssetsStatusContext(context.Background())
+}
+
+func (i *Instance) GetAssetsStatusContext(ctx context.Context) (AssetsStatus, error) {
+	var status AssetsStatus
+	url := fmt.Sprintf("%s/admin/assets/status", i.URL)
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return status, err
+	}
+	err = json.Unmarshal(resp, &status)
+	return status, err
+}
+
+/* AssetSizePerGroup sums up the asset sizes in bytes per job group ID
+ * Assets referenced by multiple job groups count for each of them. Requires the assets from GetAssetsStatus
+ */
+func AssetSizePerGroup(assets []Asset) map[int]int64 {
+	ret := make(map[int]int64, 0)
+	for _, asset := range assets {
+		for group := range asset.Groups {
+			ret[group] += int64(asset.Size)
+		}
+	}
+	return ret
+}
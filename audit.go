@@ -0,0 +1,142 @@
+package gopenqa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default number of audit events per request
+const defaultAuditPageSize = 100
+
+/* Audit event, e.g. a change of a job template, machine or product */
+type AuditEvent struct {
+	ID           int64  `json:"id"`
+	User         string `json:"user"`
+	ConnectionID string `json:"connection_id"`
+	Event        string `json:"event"`      // Event type, e.g. table_create, table_update, table_delete or jobtemplate_create
+	EventData    string `json:"event_data"` // Event details as JSON string
+	EventTime    string `json:"event_time"`
+}
+
+/* Time returns the time of the event or the zero time, if not present */
+func (e *AuditEvent) Time() time.Time {
+	return parseTimestamp(e.EventTime)
+}
+
+/* Data decodes the event details */
+func (e *AuditEvent) Data() (map[string]interface{}, error) {
+	data := make(map[string]interface{}, 0)
+	if e.EventData == "" {
+		return data, nil
+	}
+	err := json.Unmarshal([]byte(e.EventData), &data)
+	return data, err
+}
+
+/* Query for audit events. Empty fields are not used for filtering */
+type AuditQuery struct {
+	User          string    // Events of this user only
+	Event         string    // Events of this type only, e.g. table_update
+	Newer         time.Time // Events after this time only
+	Older         time.Time // Events before this time only
+	NewerRelative string    // Events after this relative time only, e.g. "yesterday" or "2 weeks ago". Used if Newer is not set
+	OlderRelative string    // Events before this relative time only. Used if Older is not set
+	Text          string    // Free text search
+	Offset        int       // Number of events to skip
+	Limit         int       // Maximum number of events to return (default: 100)
+}
+
+// search returns the search string in the openQA audit log syntax, e.g. "user:admin event:table_update newer:2023-01-09T10:00:00Z"
+func (q *AuditQuery) search() string {
+	terms := make([]string, 0)
+	if q.User != "" {
+		terms = append(terms, "user:"+q.User)
+	}
+	if q.Event != "" {
+		terms = append(terms, "event:"+q.Event)
+	}
+	if !q.Newer.IsZero() {
+		terms = append(terms, "newer:"+q.Newer.UTC().Format(time.RFC3339))
+	} else if q.NewerRelative != "" {
+		terms = append(terms, "newer:"+q.NewerRelative)
+	}
+	if !q.Older.IsZero() {
+		terms = append(terms, "older:"+q.Older.UTC().Format(time.RFC3339))
+	} else if q.OlderRelative != "" {
+		terms = append(terms, "older:"+q.OlderRelative)
+	}
+	if q.Text != "" {
+		terms = append(terms, q.Text)
+	}
+	return strings.Join(terms, " ")
+}
+
+// values returns the query as parameters for the audit log ajax call
+func (q *AuditQuery) values() url.Values {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultAuditPageSize
+	}
+	params := url.Values{}
+	params.Set("start", fmt.Sprintf("%d", q.Offset))
+	params.Set("length", fmt.Sprintf("%d", limit))
+	params.Set("search[value]", q.search())
+	return params
+}
+
+/* Page of audit events */
+type AuditEvents struct {
+	Events []AuditEvent
+	Total  int // Total number of events matching the query
+}
+
+/* Query audit events, newest first. Use AuditQuery.Offset and AuditQuery.Limit for paging. Requires SetSessionCookie */
+func (i *Instance) GetAuditEvents(q AuditQuery) (AuditEvents, error) {
+	return i.GetAuditEventsContext(context.Background(), q)
+}
+
+func (i *Instance) GetAuditEventsContext(ctx context.Context, q AuditQuery) (AuditEvents, error) {
+	ret := AuditEvents{Events: make([]AuditEvent, 0)}
+	url := fmt.Sprintf("%s/admin/auditlog/ajax?%s", i.URL, q.values().Encode())
+	var page struct { // Expected result structure
+		RecordsFiltered int          `json:"recordsFiltered"`
+		Data            []AuditEvent `json:"data"`
+	}
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return ret, err
+	}
+	if err := json.Unmarshal(resp, &page); err != nil {
+		return ret, err
+	}
+	if page.Data != nil {
+		ret.Events = page.Data
+	}
+	ret.Total = page.RecordsFiltered
+	return ret, nil
+}
+
+/* Query all audit events matching the query by paging through them. q.Limit is used as page size
+ */
+func (i *Instance) GetAllAuditEvents(q AuditQuery) ([]AuditEvent, error) {
+	return i.GetAllAuditEventsContext(context.Background(), q)
+}
+
+func (i *Instance) GetAllAuditEventsContext(ctx context.Context, q AuditQuery) ([]AuditEvent, error) {
+	ret := make([]AuditEvent, 0)
+	for {
+		page, err := i.GetAuditEventsContext(ctx, q)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, page.Events...)
+		q.Offset += len(page.Events)
+		if len(page.Events) == 0 || q.Offset >= page.Total {
+			return ret, nil
+		}
+	}
+}
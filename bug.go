@@ -0,0 +1,153 @@
+package gopenqa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+/* Bug as tracked by openQA for bug references in comments */
+type Bug struct {
+	ID         int      `json:"id"`
+	BugID      string   `json:"bugid"` // Bug reference, e.g. bsc#1234 or poo#1234
+	Title      string   `json:"title"`
+	Priority   string   `json:"priority"`
+	Assigned   FlexBool `json:"assigned"`
+	Assignee   string   `json:"assignee"`
+	Open       FlexBool `json:"open"`
+	Status     string   `json:"status"`
+	Resolution string   `json:"resolution"`
+	Existing   FlexBool `json:"existing"`  // false if the bug tracker doesn't know the bug
+	Refreshed  FlexBool `json:"refreshed"` // false if the details have not been fetched from the bug tracker yet
+	Tcreated   string   `json:"t_created"`
+	Tupdated   string   `json:"t_updated"` // Time of the last refresh
+}
+
+/* IsClosed returns true if the bug is known to be closed. Bugs that have not been refreshed yet are never closed */
+func (b *Bug) IsClosed() bool {
+	return bool(b.Refreshed) && bool(b.Existing) && !bool(b.Open)
+}
+
+/* UpdatedAt returns the time of the last refresh or the zero time, if not present */
+func (b *Bug) UpdatedAt() time.Time {
+	return parseTimestamp(b.Tupdated)
+}
+
+/* Get all bugs known to openQA, ordered by their ID. Only ID and BugID are set, use GetBug for the details */
+func (i *Instance) GetBugs() ([]Bug, error) {
+	return i.GetBugsContext(context.Background())
+}
+
+func (i *Instance) GetBugsContext(ctx context.Context) ([]Bug, error) {
+	url := fmt.Sprintf("%s/api/v1/bugs", i.URL)
+	return i.fetchBugs(ctx, url)
+}
+
+/* Get the bugs that need a refresh, i.e. that have not been refreshed yet or not within the given duration
+ * Durations below one second are sent as one second. Only ID and BugID are set, see GetBugs
+ */
+func (i *Instance) GetRefreshableBugs(delta time.Duration) ([]Bug, error) {
+	return i.GetRefreshableBugsContext(context.Background(), delta)
+}
+
+func (i *Instance) GetRefreshableBugsContext(ctx context.Context, delta time.Duration) ([]Bug, error) {
+	// openQA falls back to its default for a missing or 0 delta
+	seconds := int64(delta.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	url := fmt.Sprintf("%s/api/v1/bugs?refreshable=1&delta=%d", i.URL, seconds)
+	return i.fetchBugs(ctx, url)
+}
+
+func (i *Instance) fetchBugs(ctx context.Context, url string) ([]Bug, error) {
+	var bugs struct { // Expected result structure
+		Bugs map[int]string `json:"bugs"`
+	}
+	ret := make([]Bug, 0)
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return ret, err
+	}
+	if err := json.Unmarshal(resp, &bugs); err != nil {
+		return ret, err
+	}
+	for id, bugref := range bugs.Bugs {
+		ret = append(ret, Bug{ID: id, BugID: bugref})
+	}
+	sort.Slice(ret, func(a, b int) bool { return ret[a].ID < ret[b].ID })
+	return ret, nil
+}
+
+/* Get a single bug by its ID */
+func (i *Instance) GetBug(id int) (Bug, error) {
+	return i.GetBugContext(context.Background(), id)
+}
+
+func (i *Instance) GetBugContext(ctx context.Context, id int) (Bug, error) {
+	var bug Bug
+	url := fmt.Sprintf("%s/api/v1/bugs/%d", i.URL, id)
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return bug, err
+	}
+	err = json.Unmarshal(resp, &bug)
+	return bug, err
+}
+
+/* Resolve the bug references of the given comment into bugs
+ * This needs the list of all bugs and one request per bug reference. Use ResolveBugRefs to resolve the references of many comments at once
+ */
+func (i *Instance) GetCommentBugs(comment Comment) ([]Bug, error) {
+	return i.GetCommentBugsContext(context.Background(), comment)
+}
+
+func (i *Instance) GetCommentBugsContext(ctx context.Context, comment Comment) ([]Bug, error) {
+	ret := make([]Bug, 0)
+	bugs, err := i.ResolveBugRefsContext(ctx, comment.BugRefs)
+	if err != nil {
+		return ret, err
+	}
+	for _, bugref := range comment.BugRefs {
+		if bug, ok := bugs[bugref]; ok {
+			ret = append(ret, bug)
+		}
+	}
+	return ret, nil
+}
+
+/* Resolve the given bug references into bugs by their bug reference. Bug references that are unknown to openQA are skipped
+ * openQA has no lookup by bug reference, so this fetches the list of all bugs once and then each distinct referenced bug
+ */
+func (i *Instance) ResolveBugRefs(bugrefs []string) (map[string]Bug, error) {
+	return i.ResolveBugRefsContext(context.Background(), bugrefs)
+}
+
+func (i *Instance) ResolveBugRefsContext(ctx context.Context, bugrefs []string) (map[string]Bug, error) {
+	ret := make(map[string]Bug, 0)
+	if len(bugrefs) == 0 {
+		return ret, nil
+	}
+	bugs, err := i.GetBugsContext(ctx)
+	if err != nil {
+		return ret, err
+	}
+	ids := make(map[string]int, len(bugs))
+	for _, bug := range bugs {
+		ids[bug.BugID] = bug.ID
+	}
+	for _, bugref := range bugrefs {
+		id, ok := ids[bugref]
+		if _, done := ret[bugref]; !ok || done {
+			continue
+		}
+		bug, err := i.GetBugContext(ctx, id)
+		if err != nil {
+			return ret, err
+		}
+		ret[bugref] = bug
+	}
+	return ret, nil
+}
@@ -0,0 +1,156 @@
+package gopenqa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	"unicode"
+)
+
+/* Aggregated job results of a build, as shown on the group overview page */
+type BuildResult struct {
+	Key        string   `json:"key"`
+	Build      string   `json:"build"`
+	Version    string   `json:"version"`
+	Oldest     string   `json:"oldest"` // Creation time of the oldest job of the build
+	Total      FlexInt  `json:"total"`
+	Passed     FlexInt  `json:"passed"`
+	Failed     FlexInt  `json:"failed"`
+	Softfailed FlexInt  `json:"softfailed"`
+	Unfinished FlexInt  `json:"unfinished"` // Scheduled or running jobs
+	Skipped    FlexInt  `json:"skipped"`
+	Labeled    FlexInt  `json:"labeled"`   // Failed jobs with a label or bug reference
+	Reviewed   FlexBool `json:"reviewed"`  // All failed jobs are labeled
+	Commented  FlexBool `json:"commented"` // At least one job has a comment
+	AllPassed  FlexBool `json:"all_passed"`
+	// Results per child job group, only present for parent job groups
+	Children map[int]BuildResult `json:"children"`
+}
+
+/* OldestTime returns the creation time of the oldest job of the build or the zero time, if not present */
+func (b *BuildResult) OldestTime() time.Time {
+	return parseTimestamp(b.Oldest)
+}
+
+/* IsFinished returns true if no job of the build is scheduled or running anymore */
+func (b *BuildResult) IsFinished() bool {
+	return b.Unfinished == 0
+}
+
+/* Get the build results of a job group, newest build first. limit restricts the number of builds, 0 means openQA's default
+ * The builds are ordered by version, if group.BuildVersionSort is set, otherwise by time
+ */
+func (i *Instance) GetJobGroupBuildResults(group JobGroup, limit int) ([]BuildResult, error) {
+	return i.GetJobGroupBuildResultsContext(context.Background(), group, limit)
+}
+
+func (i *Instance) GetJobGroupBuildResultsContext(ctx context.Context, group JobGroup, limit int) ([]BuildResult, error) {
+	url := fmt.Sprintf("%s/group_overview/%d.json", i.URL, group.ID)
+	return i.fetchBuildResults(ctx, url, limit, group.BuildVersionSort != 0)
+}
+
+/* Get the build results of a parent job group, newest build first. See GetJobGroupBuildResults */
+func (i *Instance) GetParentJobGroupBuildResults(group JobGroup, limit int) ([]BuildResult, error) {
+	return i.GetParentJobGroupBuildResultsContext(context.Background(), group, limit)
+}
+
+func (i *Instance) GetParentJobGroupBuildResultsContext(ctx context.Context, group JobGroup, limit int) ([]BuildResult, error) {
+	url := fmt.Sprintf("%s/parent_group_overview/%d.json", i.URL, group.ID)
+	return i.fetchBuildResults(ctx, url, limit, group.BuildVersionSort != 0)
+}
+
+func (i *Instance) fetchBuildResults(ctx context.Context, url string, limit int, byVersion bool) ([]BuildResult, error) {
+	if limit > 0 {
+		url += fmt.Sprintf("?limit_builds=%d", limit)
+	}
+	var overview struct { // Expected result structure
+		BuildResults []BuildResult `json:"build_results"`
+	}
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return make([]BuildResult, 0), err
+	}
+	if err := json.Unmarshal(resp, &overview); err != nil {
+		return make([]BuildResult, 0), err
+	}
+	if overview.BuildResults == nil {
+		overview.BuildResults = make([]BuildResult, 0)
+	}
+	SortBuildResults(overview.BuildResults, byVersion)
+	return overview.BuildResults, nil
+}
+
+/* SortBuildResults sorts the builds newest first, either by their version and build or by the time of their oldest job
+ * This is the order openQA uses for a job group depending on its BuildVersionSort setting
+ */
+func SortBuildResults(builds []BuildResult, byVersion bool) {
+	sort.SliceStable(builds, func(a, b int) bool {
+		if byVersion {
+			if c := compareVersions(builds[a].Version, builds[b].Version); c != 0 {
+				return c > 0
+			}
+			return compareVersions(builds[a].Build, builds[b].Build) > 0
+		}
+		return builds[a].OldestTime().After(builds[b].OldestTime())
+	})
+}
+
+// compareVersions compares two version strings by their numeric and alphabetic parts. Returns -1, 0 or 1
+func compareVersions(v1 string, v2 string) int {
+	p1, p2 := versionParts(v1), versionParts(v2)
+	for i := 0; i < len(p1) && i < len(p2); i++ {
+		n1, err1 := strconv.ParseUint(p1[i], 10, 64)
+		n2, err2 := strconv.ParseUint(p2[i], 10, 64)
+		if err1 == nil && err2 == nil {
+			if n1 != n2 {
+				if n1 < n2 {
+					return -1
+				}
+				return 1
+			}
+		} else if err1 == nil {
+			// Numbers are considered newer than letters, e.g. 1.1 > 1.a
+			return 1
+		} else if err2 == nil {
+			return -1
+		} else if p1[i] != p2[i] {
+			if p1[i] < p2[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	if len(p1) < len(p2) {
+		return -1
+	} else if len(p1) > len(p2) {
+		return 1
+	}
+	return 0
+}
+
+// versionParts splits a version into runs of digits and letters. All other characters are separators
+func versionParts(version string) []string {
+	parts := make([]string, 0)
+	current := make([]rune, 0)
+	digits := false
+	for _, r := range version {
+		isDigit, isLetter := unicode.IsDigit(r), unicode.IsLetter(r)
+		if (!isDigit && !isLetter) || (len(current) > 0 && isDigit != digits) {
+			if len(current) > 0 {
+				parts = append(parts, string(current))
+				current = current[:0]
+			}
+		}
+		if isDigit || isLetter {
+			current = append(current, r)
+			digits = isDigit
+		}
+	}
+	if len(current) > 0 {
+		parts = append(parts, string(current))
+	}
+	return parts
+}
@@ -90,12 +90,14 @@ func usage() {
 	fmt.Println("ENTITY")
 	fmt.Println("")
 	fmt.Println("  job [ID]")
+	fmt.Println("  job graph ID [dot|mermaid]")
 	fmt.Println("  jobs [IDS...]")
 	fmt.Println("  jobgroup(s)")
 	fmt.Println("  machine(s)")
 	fmt.Println("  product(s) | medium(s)")
+	fmt.Println("  testsuite(s)")
 	fmt.Println("  parentgroup(s)")
-	fmt.Println("  comments")
+	fmt.Println("  comments [GET|POST|PUT|DELETE] JOB [COMMENT] [TEXT]")
 	fmt.Println("  jobstate")
 }
 
@@ -268,6 +270,47 @@ func readJobGroups(filename string) ([]gopenqa.JobGroup, error) {
 	return jobgroups, fmt.Errorf("invalid input format")
 }
 
+/* Read test suites from stdin */
+func readTestSuites(filename string) ([]gopenqa.TestSuite, error) {
+	var data []byte
+	var err error
+
+	if filename == "" {
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return make([]gopenqa.TestSuite, 0), err
+		}
+	} else {
+		// TODO: Don't use io.ReadAll
+		if file, err := os.Open(filename); err != nil {
+			return make([]gopenqa.TestSuite, 0), err
+		} else {
+			defer file.Close()
+			data, err = io.ReadAll(file)
+			if err != nil {
+				return make([]gopenqa.TestSuite, 0), err
+			}
+		}
+	}
+
+	// First try to read a single test suite
+	var testsuite gopenqa.TestSuite
+	if err := json.Unmarshal(data, &testsuite); err == nil {
+		testsuites := make([]gopenqa.TestSuite, 0)
+		testsuites = append(testsuites, testsuite)
+		return testsuites, nil
+	}
+
+	// Then try to read a test suite array
+	var testsuites []gopenqa.TestSuite
+	if err := json.Unmarshal(data, &testsuites); err == nil {
+		return testsuites, err
+	}
+
+	testsuites = make([]gopenqa.TestSuite, 0)
+	return testsuites, fmt.Errorf("invalid input format")
+}
+
 func printJson(data interface{}) error {
 	// Print as json
 	if buf, err := json.Marshal(data); err != nil {
@@ -482,6 +525,103 @@ func runMachine(args []string) error {
 	}
 }
 
+func postTestSuites(args []string) error {
+	files := args
+	if len(files) == 0 {
+		files = append(files, "")
+	}
+
+	for _, filename := range files {
+		if testsuites, err := readTestSuites(filename); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+		} else {
+			for _, testsuite := range testsuites {
+				if testsuite, err := instance.PostTestSuite(testsuite); err != nil {
+					return err
+				} else {
+					fmt.Printf("Posted test suite %d %s\n", testsuite.ID, testsuite.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func runTestSuites(args []string) error {
+	method := "GET"
+
+	if len(args) > 0 {
+		// get method
+		method = args[0]
+		args = args[1:]
+	}
+
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "GET" {
+		if testsuites, err := instance.GetTestSuites(); err != nil {
+			return err
+		} else {
+			return printJson(testsuites)
+		}
+	} else if method == "POST" {
+		return postTestSuites(args)
+	} else if method == "DELETE" {
+		ids, _ := extractIntegers(args)
+		if len(ids) == 0 {
+			fmt.Fprintf(os.Stderr, "Missing test suite ids\n")
+		} else {
+			for _, id := range ids {
+				if err := instance.DeleteTestSuite(id); err != nil {
+					return err
+				} else {
+					fmt.Printf("Deleted test suite %d\n", id)
+				}
+			}
+		}
+		return nil
+	} else {
+		return fmt.Errorf("invalid method: %s", method)
+	}
+}
+
+func runTestSuite(args []string) error {
+	method := "GET"
+	ids, args := extractIntegers(args)
+
+	if len(args) > 0 {
+		// get method
+		method = args[0]
+		args = args[1:]
+	}
+
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "GET" {
+		for _, id := range ids {
+			if testsuite, err := instance.GetTestSuite(id); err != nil {
+				return err
+			} else {
+				if err := printJson(testsuite); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	} else if method == "POST" {
+		return postTestSuites(args)
+	} else if method == "DELETE" {
+		for _, id := range ids {
+			if err := instance.DeleteTestSuite(id); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted test suite %d\n", id)
+		}
+		return nil
+	} else {
+		return fmt.Errorf("invalid method: %s", method)
+	}
+}
+
 func runJobGroups(args []string) error {
 	method := "GET"
 
@@ -724,6 +864,46 @@ func runComments(args []string) error {
 			return err
 		}
 		return nil
+	} else if method == "POST" {
+		if len(args) < 1 {
+			return fmt.Errorf("missing comment text")
+		}
+		comment := gopenqa.Comment{Text: strings.Join(args, " ")}
+		cid, err := instance.PostComment(id, comment)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Posted comment %d\n", cid)
+		return nil
+	} else if method == "PUT" {
+		if len(args) < 2 {
+			return fmt.Errorf("missing comment id or text")
+		}
+		cid, err := strconv.Atoi(args[0])
+		if err != nil || cid <= 0 {
+			return fmt.Errorf("invalid comment ID")
+		}
+		comment := gopenqa.Comment{ID: cid, Text: strings.Join(args[1:], " ")}
+		if err := instance.UpdateComment(id, comment); err != nil {
+			return err
+		}
+		fmt.Printf("Updated comment %d\n", cid)
+		return nil
+	} else if method == "DELETE" {
+		cids, args := extractIntegers(args)
+		if len(args) > 0 {
+			return fmt.Errorf("invalid arguments")
+		}
+		if len(cids) == 0 {
+			return fmt.Errorf("missing comment ids")
+		}
+		for _, cid := range cids {
+			if err := instance.DeleteComment(id, cid); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted comment %d\n", cid)
+		}
+		return nil
 	} else {
 		return fmt.Errorf("Method %s is not (yet) supported", method)
 	}
@@ -760,6 +940,9 @@ func runJobState(args []string) error {
 func runJob(args []string) error {
 
 	var id int64
+	if len(args) > 0 && args[0] == "graph" {
+		return runJobGraph(args[1:])
+	}
 	if len(args) < 1 {
 		return fmt.Errorf("missing argument: job")
 	} else if len(args) > 1 {
@@ -779,6 +962,36 @@ func runJob(args []string) error {
 	return nil
 }
 
+func runJobGraph(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing argument: job")
+	} else if len(args) > 2 {
+		return fmt.Errorf("too many arguments")
+	}
+	id, _ := strconv.ParseInt(args[0], 10, 64)
+	if id <= 0 {
+		return fmt.Errorf("invalid ID")
+	}
+	format := "dot"
+	if len(args) > 1 {
+		format = strings.ToLower(args[1])
+	}
+	if format != "dot" && format != "mermaid" {
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	graph, err := instance.GetJobGraph(id, true)
+	if err != nil {
+		return err
+	}
+	if format == "mermaid" {
+		fmt.Print(graph.Mermaid())
+	} else {
+		fmt.Print(graph.DOT())
+	}
+	return nil
+}
+
 func runJobs(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("missing argument: jobs")
@@ -848,6 +1061,16 @@ func main() {
 			fmt.Fprintf(os.Stderr, "%s\n", err)
 			os.Exit(1)
 		}
+	} else if entity == "testsuites" || entity == "test_suites" {
+		if err := runTestSuites(command); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+	} else if entity == "testsuite" || entity == "test_suite" {
+		if err := runTestSuite(command); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
 	} else if entity == "jobgroups" || entity == "job_groups" {
 		if err := runJobGroups(command); err != nil {
 			fmt.Fprintf(os.Stderr, "%s\n", err)
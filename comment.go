@@ -1,5 +1,13 @@
 package gopenqa
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+)
+
 type Comment struct {
 	ID       int      `json:"id"`
 	Text     string   `json:"text"`             // Comment text
@@ -9,3 +17,168 @@ type Comment struct {
 	Updated  string   `json:"updated"`          // timestamp for update
 	User     string   `json:"userName"`         // Creator
 }
+
+func (i *Instance) fetchComments(ctx context.Context, url string) ([]Comment, error) {
+	ret := make([]Comment, 0)
+	buf, err := i.get(ctx, url, nil)
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	if err != nil {
+		return ret, err
+	}
+	err = json.Unmarshal(buf, &ret)
+	return ret, err
+}
+
+// postComment posts the comment text to the given comments url and returns the ID of the created comment
+func (i *Instance) postComment(ctx context.Context, rurl string, comment Comment) (int, error) {
+	params := url.Values{}
+	params.Add("text", comment.Text)
+	buf, err := i.post(ctx, rurl, []byte(params.Encode()))
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	if err != nil {
+		return 0, err
+	}
+	var obj struct { // Result: {"id":42}
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(buf, &obj); err != nil {
+		return 0, err
+	}
+	if obj.ID == 0 {
+		return 0, ErrInvalidResponse
+	}
+	return obj.ID, nil
+}
+
+// updateComment replaces the text of the comment given by its url
+func (i *Instance) updateComment(ctx context.Context, rurl string, comment Comment) error {
+	params := url.Values{}
+	params.Add("text", comment.Text)
+	buf, err := i.put(ctx, rurl, []byte(params.Encode()))
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	return err
+}
+
+func (i *Instance) deleteComment(ctx context.Context, rurl string) error {
+	buf, err := i.delete(ctx, rurl, nil)
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	return err
+}
+
+/* Post a new comment on the given job and return the ID of the created comment. Only comment.Text is used */
+func (i *Instance) PostComment(job int64, comment Comment) (int, error) {
+	return i.PostCommentContext(context.Background(), job, comment)
+}
+
+func (i *Instance) PostCommentContext(ctx context.Context, job int64, comment Comment) (int, error) {
+	rurl := fmt.Sprintf("%s/api/v1/jobs/%d/comments", i.URL, job)
+	return i.postComment(ctx, rurl, comment)
+}
+
+/* Update the text of an existing comment of the given job. The comment is identified by comment.ID */
+func (i *Instance) UpdateComment(job int64, comment Comment) error {
+	return i.UpdateCommentContext(context.Background(), job, comment)
+}
+
+func (i *Instance) UpdateCommentContext(ctx context.Context, job int64, comment Comment) error {
+	rurl := fmt.Sprintf("%s/api/v1/jobs/%d/comments/%d", i.URL, job, comment.ID)
+	return i.updateComment(ctx, rurl, comment)
+}
+
+/* Delete the given comment of a job */
+func (i *Instance) DeleteComment(job int64, id int) error {
+	return i.DeleteCommentContext(context.Background(), job, id)
+}
+
+func (i *Instance) DeleteCommentContext(ctx context.Context, job int64, id int) error {
+	rurl := fmt.Sprintf("%s/api/v1/jobs/%d/comments/%d", i.URL, job, id)
+	return i.deleteComment(ctx, rurl)
+}
+
+/* Fetch comments for a given job group */
+func (i *Instance) GetJobGroupComments(group int) ([]Comment, error) {
+	return i.GetJobGroupCommentsContext(context.Background(), group)
+}
+
+func (i *Instance) GetJobGroupCommentsContext(ctx context.Context, group int) ([]Comment, error) {
+	rurl := fmt.Sprintf("%s/api/v1/groups/%d/comments", i.URL, group)
+	return i.fetchComments(ctx, rurl)
+}
+
+/* Post a new comment on the given job group and return the ID of the created comment. Only comment.Text is used */
+func (i *Instance) PostJobGroupComment(group int, comment Comment) (int, error) {
+	return i.PostJobGroupCommentContext(context.Background(), group, comment)
+}
+
+func (i *Instance) PostJobGroupCommentContext(ctx context.Context, group int, comment Comment) (int, error) {
+	rurl := fmt.Sprintf("%s/api/v1/groups/%d/comments", i.URL, group)
+	return i.postComment(ctx, rurl, comment)
+}
+
+/* Update the text of an existing comment of the given job group. The comment is identified by comment.ID */
+func (i *Instance) UpdateJobGroupComment(group int, comment Comment) error {
+	return i.UpdateJobGroupCommentContext(context.Background(), group, comment)
+}
+
+func (i *Instance) UpdateJobGroupCommentContext(ctx context.Context, group int, comment Comment) error {
+	rurl := fmt.Sprintf("%s/api/v1/groups/%d/comments/%d", i.URL, group, comment.ID)
+	return i.updateComment(ctx, rurl, comment)
+}
+
+/* Delete the given comment of a job group */
+func (i *Instance) DeleteJobGroupComment(group int, id int) error {
+	return i.DeleteJobGroupCommentContext(context.Background(), group, id)
+}
+
+func (i *Instance) DeleteJobGroupCommentContext(ctx context.Context, group int, id int) error {
+	rurl := fmt.Sprintf("%s/api/v1/groups/%d/comments/%d", i.URL, group, id)
+	return i.deleteComment(ctx, rurl)
+}
+
+/* Fetch comments for a given parent job group */
+func (i *Instance) GetParentJobGroupComments(group int) ([]Comment, error) {
+	return i.GetParentJobGroupCommentsContext(context.Background(), group)
+}
+
+func (i *Instance) GetParentJobGroupCommentsContext(ctx context.Context, group int) ([]Comment, error) {
+	rurl := fmt.Sprintf("%s/api/v1/parent_groups/%d/comments", i.URL, group)
+	return i.fetchComments(ctx, rurl)
+}
+
+/* Post a new comment on the given parent job group and return the ID of the created comment. Only comment.Text is used */
+func (i *Instance) PostParentJobGroupComment(group int, comment Comment) (int, error) {
+	return i.PostParentJobGroupCommentContext(context.Background(), group, comment)
+}
+
+func (i *Instance) PostParentJobGroupCommentContext(ctx context.Context, group int, comment Comment) (int, error) {
+	rurl := fmt.Sprintf("%s/api/v1/parent_groups/%d/comments", i.URL, group)
+	return i.postComment(ctx, rurl, comment)
+}
+
+/* Update the text of an existing comment of the given parent job group. The comment is identified by comment.ID */
+func (i *Instance) UpdateParentJobGroupComment(group int, comment Comment) error {
+	return i.UpdateParentJobGroupCommentContext(context.Background(), group, comment)
+}
+
+func (i *Instance) UpdateParentJobGroupCommentContext(ctx context.Context, group int, comment Comment) error {
+	rurl := fmt.Sprintf("%s/api/v1/parent_groups/%d/comments/%d", i.URL, group, comment.ID)
+	return i.updateComment(ctx, rurl, comment)
+}
+
+/* Delete the given comment of a parent job group */
+func (i *Instance) DeleteParentJobGroupComment(group int, id int) error {
+	return i.DeleteParentJobGroupCommentContext(context.Background(), group, id)
+}
+
+func (i *Instance) DeleteParentJobGroupCommentContext(ctx context.Context, group int, id int) error {
+	rurl := fmt.Sprintf("%s/api/v1/parent_groups/%d/comments/%d", i.URL, group, id)
+	return i.deleteComment(ctx, rurl)
+}
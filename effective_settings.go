@@ -0,0 +1,213 @@
+package gopenqa
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+/* Origin of a job setting */
+type SettingSource string
+
+const (
+	SettingFromProduct        SettingSource = "product"         // Product settings, DISTRI, VERSION, FLAVOR and ARCH
+	SettingFromMachine        SettingSource = "machine"         // Machine settings, MACHINE and BACKEND
+	SettingFromTestSuite      SettingSource = "test suite"      // Test suite settings and TEST
+	SettingFromJobTemplate    SettingSource = "job template"    // Settings of the job template itself
+	SettingFromWorkerClasses  SettingSource = "worker classes"  // WORKER_CLASS merged from all sources
+	SettingFromOverride       SettingSource = "override"        // Scheduling parameter, overrides all stored settings without + prefix
+	SettingFromForcedOverride SettingSource = "forced override" // Scheduling parameter with + prefix, overrides everything
+)
+
+/* Value of a setting from a single source */
+type SettingValue struct {
+	Value  string
+	Source SettingSource
+}
+
+/* Final value of a job setting, including where it came from */
+type EffectiveSetting struct {
+	Key      string
+	Value    string
+	Source   SettingSource
+	Shadowed []SettingValue // Values of sources with lower precedence, that have been overridden
+}
+
+/* Effective settings by their key */
+type EffectiveSettings map[string]EffectiveSetting
+
+/* Keys returns the setting keys in alphabetical order */
+func (s EffectiveSettings) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/* Values returns the final setting values by their key */
+func (s EffectiveSettings) Values() map[string]string {
+	ret := make(map[string]string, len(s))
+	for key, setting := range s {
+		ret[key] = setting.Value
+	}
+	return ret
+}
+
+// set applies the given value with a higher precedence than the current one
+func (s EffectiveSettings) set(key string, value string, source SettingSource) {
+	key = strings.ToUpper(key)
+	setting, ok := s[key]
+	if ok {
+		setting.Shadowed = append(setting.Shadowed, SettingValue{Value: setting.Value, Source: setting.Source})
+	}
+	setting.Key, setting.Value, setting.Source = key, value, source
+	s[key] = setting
+}
+
+// setAll applies all given settings, ignoring empty keys
+func (s EffectiveSettings) setAll(settings map[string]string, source SettingSource) {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // Reproducible results for keys that differ only in case
+	for _, key := range keys {
+		if key != "" {
+			s.set(key, settings[key], source)
+		}
+	}
+}
+
+/* EffectiveSettings resolves the settings of a job created from this template in the order openQA uses when scheduling:
+ * product, machine, test suite, job template and finally the overrides, i.e. the scheduling parameters.
+ * Worker classes of product, machine, test suite and job template are always merged instead of overridden, also the ones with + prefix.
+ * Settings with + prefix override everything else, regardless of their source. Their Source is the one they come from,
+ * or SettingFromForcedOverride for scheduling parameters.
+ * The job template needs to contain the full product, machine and test suite, see Instance.GetEffectiveSettings
+ */
+func (t *JobTemplate) EffectiveSettings(overrides map[string]string) EffectiveSettings {
+	s := make(EffectiveSettings, 0)
+	layers := []settingLayer{
+		{t.Product.Settings, SettingFromProduct},
+		{nonEmpty(map[string]string{"DISTRI": t.Product.Distri, "VERSION": t.Product.Version, "FLAVOR": t.Product.Flavor, "ARCH": t.Product.Arch}), SettingFromProduct},
+		{t.Machine.Settings, SettingFromMachine},
+		{nonEmpty(map[string]string{"MACHINE": t.Machine.Name, "BACKEND": t.Machine.Backend}), SettingFromMachine},
+		{t.TestSuite.Settings, SettingFromTestSuite},
+		{nonEmpty(map[string]string{"TEST": t.TestSuite.Name}), SettingFromTestSuite},
+		{t.Settings, SettingFromJobTemplate},
+	}
+	// Settings with + prefix are applied last, in the same order as the others
+	forced := make([]settingLayer, 0, len(layers))
+	for _, layer := range layers {
+		plain, plus := splitForced(layer.settings)
+		s.setAll(plain, layer.source)
+		forced = append(forced, settingLayer{settings: plus, source: layer.source})
+	}
+
+	// Worker classes add up, also the ones with + prefix
+	classes := make([]string, 0)
+	for _, settings := range []map[string]string{t.Product.Settings, t.Machine.Settings, t.TestSuite.Settings, t.Settings} {
+		for key, value := range settings {
+			if isWorkerClass(key) {
+				classes = append(classes, strings.Split(value, ",")...)
+			}
+		}
+	}
+	if classes = uniqueSorted(classes); len(classes) > 0 {
+		s.set("WORKER_CLASS", strings.Join(classes, ","), SettingFromWorkerClasses)
+	}
+
+	plain, plus := splitForced(overrides)
+	s.setAll(plain, SettingFromOverride)
+	for _, layer := range forced {
+		for key := range layer.settings {
+			if isWorkerClass(key) {
+				// Already part of the merged worker classes
+				delete(layer.settings, key)
+			}
+		}
+		s.setAll(layer.settings, layer.source)
+	}
+	s.setAll(plus, SettingFromForcedOverride)
+	return s
+}
+
+// settingLayer holds the settings of a single source
+type settingLayer struct {
+	settings map[string]string
+	source   SettingSource
+}
+
+// splitForced splits the given settings into the ones without and the ones with + prefix. The prefix is removed
+func splitForced(settings map[string]string) (map[string]string, map[string]string) {
+	plain, forced := make(map[string]string, 0), make(map[string]string, 0)
+	for key, value := range settings {
+		if strings.HasPrefix(key, "+") {
+			forced[strings.TrimPrefix(key, "+")] = value
+		} else {
+			plain[key] = value
+		}
+	}
+	return plain, forced
+}
+
+// isWorkerClass returns true, if the given key is WORKER_CLASS, with or without + prefix
+func isWorkerClass(key string) bool {
+	return strings.ToUpper(strings.TrimPrefix(key, "+")) == "WORKER_CLASS"
+}
+
+// nonEmpty returns the given settings without empty values
+func nonEmpty(settings map[string]string) map[string]string {
+	ret := make(map[string]string, 0)
+	for key, value := range settings {
+		if value != "" {
+			ret[key] = value
+		}
+	}
+	return ret
+}
+
+// uniqueSorted returns the sorted, non-empty and unique values
+func uniqueSorted(values []string) []string {
+	known := make(map[string]bool, 0)
+	ret := make([]string, 0)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !known[value] {
+			known[value] = true
+			ret = append(ret, value)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+/* Resolve the effective settings of the given job template. See JobTemplate.EffectiveSettings
+ * Job templates as returned by openQA only contain the IDs and names of their product, machine and test suite.
+ * Those are fetched to get their settings
+ */
+func (i *Instance) GetEffectiveSettings(template JobTemplate, overrides map[string]string) (EffectiveSettings, error) {
+	return i.GetEffectiveSettingsContext(context.Background(), template, overrides)
+}
+
+func (i *Instance) GetEffectiveSettingsContext(ctx context.Context, template JobTemplate, overrides map[string]string) (EffectiveSettings, error) {
+	var err error
+	if template.Product.ID > 0 {
+		if template.Product, err = i.GetProductContext(ctx, template.Product.ID); err != nil {
+			return nil, err
+		}
+	}
+	if template.Machine.ID > 0 {
+		if template.Machine, err = i.GetMachineContext(ctx, template.Machine.ID); err != nil {
+			return nil, err
+		}
+	}
+	if template.TestSuite.ID > 0 {
+		if template.TestSuite, err = i.GetTestSuiteContext(ctx, template.TestSuite.ID); err != nil {
+			return nil, err
+		}
+	}
+	return template.EffectiveSettings(overrides), nil
+}
@@ -0,0 +1,67 @@
+package gopenqa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors for use with errors.Is
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrInvalidResponse = errors.New("invalid response")
+	ErrDependencyCycle = errors.New("dependency cycle")
+)
+
+// APIError is returned for every request that is answered by openQA with a non-200 status code
+type APIError struct {
+	StatusCode int    // HTTP status code
+	Method     string // HTTP method of the request
+	URL        string // Requested URL
+	Message    string // Error message returned by openQA, if present
+	Body       []byte // Raw response body
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s: http status code %d: %s", e.Method, e.URL, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s %s: http status code %d", e.Method, e.URL, e.StatusCode)
+}
+
+// Is allows to check an APIError against the sentinel errors with errors.Is
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// newAPIError creates a APIError and tries to decode the openQA error message from the response body
+func newAPIError(method string, url string, statusCode int, body []byte) *APIError {
+	err := &APIError{StatusCode: statusCode, Method: method, URL: url, Body: body}
+	// openQA returns errors as {"error":"...","error_status":404}
+	var obj struct {
+		Error interface{} `json:"error"`
+	}
+	if json.Unmarshal(body, &obj) == nil {
+		switch msg := obj.Error.(type) {
+		case string:
+			err.Message = msg
+		case nil:
+		default:
+			if buf, e := json.Marshal(msg); e == nil {
+				err.Message = string(buf)
+			}
+		}
+	}
+	return err
+}
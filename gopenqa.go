@@ -1,12 +1,20 @@
+/* Package gopenqa is a client library for the openQA API
+ *
+ * Every call that performs requests has a variant with the Context suffix, which takes a context as first argument.
+ * Cancelling the context aborts requests in flight as well as waiting between requests, e.g. for retries or job updates.
+ * The call then returns the error of the context. Calls that perform several requests return the results collected so far together with the error.
+ */
 package gopenqa
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -25,6 +33,29 @@ type Instance struct {
 	userAgent     string     // Useragent sent with the request
 	allowParallel bool       // Allow parallel requests (default: No)
 	mutFetching   sync.Mutex // Mutex to ensure only one request at the time is performed
+	client        *http.Client
+	retry         RetryPolicy  // Retry policy for failed requests (default: No retries)
+	limiter       *limiter     // Client-side rate limit (default: None)
+	session       *http.Cookie // Session cookie for web UI routes (default: None)
+}
+
+// defaultClient is the shared http client for all instances without a custom client
+var defaultClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 2 * time.Minute,
+		ExpectContinueTimeout: 1 * time.Second,
+	},
+	// No overall timeout, as this would also limit the download of large responses. Use a context instead
 }
 
 // the settings are given as dict:
@@ -48,6 +79,14 @@ type productSettings struct {
 	Settings []map[string]string `json:"settings"`
 }
 
+// same as machineSettings for TestSuite
+type testSuiteSettings struct {
+	ID          int                 `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Settings    []map[string]string `json:"settings"`
+}
+
 func convertSettingsFrom(settings map[string]string) []map[string]string {
 	ret := make([]map[string]string, 0)
 	for k, v := range settings {
@@ -89,6 +128,38 @@ func (p *productSettings) CopySettingsTo(dst *Product) {
 	dst.Settings = convertSettingsTo(p.Settings)
 }
 
+func (t *testSuiteSettings) CopySettingsFrom(src TestSuite) {
+	t.Settings = convertSettingsFrom(src.Settings)
+}
+func (t *testSuiteSettings) CopySettingsTo(dst *TestSuite) {
+	dst.Settings = convertSettingsTo(t.Settings)
+}
+
+func (t *testSuiteSettings) toTestSuite() TestSuite {
+	ts := TestSuite{ID: t.ID, Name: t.Name, Description: t.Description}
+	t.CopySettingsTo(&ts)
+	return ts
+}
+
+/* Get www-form-urlencoded parameters of this TestSuite */
+func (t *testSuiteSettings) encodeParams() string {
+	params := url.Values{}
+	params.Add("name", t.Name)
+	params.Add("description", t.Description)
+	for _, s := range t.Settings {
+		k, ok := s["key"]
+		if !ok {
+			continue
+		}
+		v, ok := s["value"]
+		if !ok {
+			continue
+		}
+		params.Add("settings["+k+"]", v)
+	}
+	return params.Encode()
+}
+
 func (w *productSettings) toProduct() Product {
 	p := Product{}
 	p.Arch = w.Arch
@@ -149,7 +220,17 @@ func EmptyParams() map[string]string {
 
 /* Create a openQA instance module */
 func CreateInstance(url string) Instance {
-	return Instance{URL: url, maxRecursions: 10, verbose: false, userAgent: "gopenqa", allowParallel: false}
+	return CreateInstanceWithClient(url, defaultClient)
+}
+
+/* Create a openQA instance module that performs all requests with the given http client
+ * Use this to configure timeouts, proxies, custom CA certificates or client certificates
+ */
+func CreateInstanceWithClient(url string, client *http.Client) Instance {
+	if client == nil {
+		client = defaultClient
+	}
+	return Instance{URL: url, maxRecursions: 10, verbose: false, userAgent: "gopenqa", allowParallel: false, client: client}
 }
 
 /* Create a openQA instance module for openqa.opensuse.org */
@@ -183,6 +264,51 @@ func (i *Instance) SetAllowParallel(allow bool) {
 	i.allowParallel = allow
 }
 
+// Set the http client used for all requests. nil restores the default client
+func (i *Instance) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		client = defaultClient
+	}
+	i.client = client
+}
+
+// Set the session cookie of a logged-in user, which is sent with all requests to the instance. nil removes the cookie
+// Some data, e.g. the asset status or the audit log, is only served by routes of the openQA web UI. Those don't accept API keys,
+// but require the session of a logged-in operator or admin.
+// openQA names its session cookie "mojolicious". Its value can be taken from a logged-in browser session
+func (i *Instance) SetSessionCookie(cookie *http.Cookie) {
+	i.session = cookie
+}
+
+// Set the policy for retrying failed requests. Use DefaultRetryPolicy() for sane defaults
+func (i *Instance) SetRetryPolicy(policy RetryPolicy) {
+	i.retry = policy
+}
+
+// Set client-side limits for the request rate and the number of concurrent requests. A zero RateLimit removes all limits
+// Note: Requests are performed one at the time unless parallel requests are allowed via SetAllowParallel,
+// so MaxInFlight has only an effect after SetAllowParallel(true)
+func (i *Instance) SetRateLimit(limit RateLimit) {
+	if limit.RequestsPerSecond <= 0 && limit.MaxInFlight <= 0 {
+		i.limiter = nil
+		return
+	}
+	i.limiter = newLimiter(limit)
+}
+
+// HTTPClient returns the http client used for all requests
+func (i *Instance) HTTPClient() *http.Client {
+	if i.client == nil {
+		return defaultClient
+	}
+	return i.client
+}
+
+// Set the RoundTripper for all requests. This creates a new http client, that uses the given transport
+func (i *Instance) SetTransport(transport http.RoundTripper) {
+	i.client = &http.Client{Transport: transport}
+}
+
 func assignInstance(jobs []Job, instance *Instance) []Job {
 	for i, j := range jobs {
 		j.instance = instance
@@ -212,34 +338,149 @@ func url_path(url string) string {
 /* Perform a GET request on the given url, and send the data as JSON if given
  * Add the APIKEY and APISECRET credentials, if given
  */
-func (i *Instance) get(url string, data []byte) ([]byte, error) {
-	return i.request("GET", url, data)
+func (i *Instance) get(ctx context.Context, url string, data []byte) ([]byte, error) {
+	return i.request(ctx, "GET", url, data)
 }
 
 /* Perform a POST request on the given url, and send the data as JSON if given
  * Add the APIKEY and APISECRET credentials, if given
  */
-func (i *Instance) post(url string, data []byte) ([]byte, error) {
-	return i.request("POST", url, data)
+func (i *Instance) post(ctx context.Context, url string, data []byte) ([]byte, error) {
+	return i.request(ctx, "POST", url, data)
+}
+
+/* Perform a PUT request on the given url, and send the data as JSON if given
+ * Add the APIKEY and APISECRET credentials, if given
+ */
+func (i *Instance) put(ctx context.Context, url string, data []byte) ([]byte, error) {
+	return i.request(ctx, "PUT", url, data)
 }
 
 /* Perform a DELETE request on the given url, and send the data as JSON if given
  * Add the APIKEY and APISECRET credentials, if given
  */
-func (i *Instance) delete(url string, data []byte) ([]byte, error) {
-	return i.request("DELETE", url, data)
+func (i *Instance) delete(ctx context.Context, url string, data []byte) ([]byte, error) {
+	return i.request(ctx, "DELETE", url, data)
 }
 
 /* Perform a request on the given url, and send the data as JSON if given
  * Add the APIKEY and APISECRET credentials, if given
+ * The request is aborted, once the given context is cancelled
  */
-func (i *Instance) request(method string, url string, data []byte) ([]byte, error) {
+func (i *Instance) request(ctx context.Context, method string, url string, data []byte) ([]byte, error) {
 	// Request mutex to ensure, only one request at the time
-	if !i.allowParallel {
+	locked := !i.allowParallel
+	if locked {
+		i.mutFetching.Lock()
+		defer i.mutFetching.Unlock()
+	}
+
+	r, err := i.send(ctx, method, url, data, locked)
+	if err != nil {
+		return make([]byte, 0), err
+	}
+
+	// First read body to have it ready in case of errors
+	defer r.Body.Close()
+	buf, err := io.ReadAll(r.Body) // TODO: Limit read size
+	if err != nil {
+		return buf, err
+	}
+
+	// Check status code
+	if r.StatusCode != 200 {
+		if i.verbose {
+			fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+		}
+		return buf, newAPIError(method, url, r.StatusCode, buf)
+	}
+	return buf, nil
+}
+
+/* Perform a request and return the response with the unread body for streaming. The caller needs to close the body
+ * In contrast to request, the request mutex is released as soon as the response headers are received
+ */
+func (i *Instance) stream(ctx context.Context, method string, url string, data []byte) (*http.Response, error) {
+	locked := !i.allowParallel
+	if locked {
 		i.mutFetching.Lock()
 		defer i.mutFetching.Unlock()
 	}
 
+	r, err := i.send(ctx, method, url, data, locked)
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode != 200 {
+		defer r.Body.Close()
+		buf, _ := io.ReadAll(r.Body)
+		if i.verbose {
+			fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+		}
+		return nil, newAPIError(method, url, r.StatusCode, buf)
+	}
+	return r, nil
+}
+
+/* Perform the request and retry it according to the retry policy of the instance
+ * Returns the response of the last attempt. The caller needs to close the response body
+ * locked indicates that the caller holds the request mutex. It is released while waiting between two attempts
+ */
+func (i *Instance) send(ctx context.Context, method string, url string, data []byte, locked bool) (*http.Response, error) {
+	attempts := i.retry.attempts(method)
+	for attempt := 1; ; attempt++ {
+		req, err := i.newRequest(ctx, method, url, data)
+		if err != nil {
+			return nil, err
+		}
+		r, err := i.do(ctx, req)
+		if attempt >= attempts || !i.retry.retryable(ctx, r, err) {
+			return r, err
+		}
+		delay := i.retry.backoff(attempt, r)
+		if r != nil {
+			// Drain the body to allow the connection to be reused
+			io.Copy(io.Discard, r.Body)
+			r.Body.Close()
+		}
+		if i.verbose {
+			fmt.Fprintf(os.Stderr, "%s %s failed (attempt %d/%d), retrying in %s\n", method, url, attempt, attempts, delay)
+		}
+		// Don't block other requests during the backoff
+		if locked {
+			i.mutFetching.Unlock()
+		}
+		err = retrySleep(ctx, delay)
+		if locked {
+			i.mutFetching.Lock()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+/* Perform a single request within the rate limit of the instance
+ * The slot of the rate limiter is held until the response body is closed
+ */
+func (i *Instance) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if i.limiter == nil {
+		return i.HTTPClient().Do(req)
+	}
+	if err := i.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	r, err := i.HTTPClient().Do(req)
+	if err != nil {
+		i.limiter.release()
+		return r, err
+	}
+	r.Body = &limitedBody{ReadCloser: r.Body, limiter: i.limiter}
+	return r, nil
+}
+
+/* Create a new signed request. The signature contains a timestamp, so this needs to be done for every attempt */
+func (i *Instance) newRequest(ctx context.Context, method string, url string, data []byte) (*http.Request, error) {
 	contentType := ""
 	if data == nil {
 		data = make([]byte, 0)
@@ -254,14 +495,21 @@ func (i *Instance) request(method string, url string, data []byte) ([]byte, erro
 		contentType = "application/x-www-form-urlencoded"
 	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(data))
 	if err != nil {
-		return make([]byte, 0), err
+		return nil, err
 	}
 	req.Header.Add("Content-Type", contentType)
 	if i.userAgent != "" {
 		req.Header.Set("User-Agent", i.userAgent)
 	}
+	// Credentials are only sent to the instance, not to other hosts e.g. of a Link header
+	if !i.isInstanceHost(req.URL) {
+		return req, nil
+	}
+	if i.session != nil {
+		req.AddCookie(i.session)
+	}
 	// Credentials are sent in the headers
 	// "X-API-Key" -> api key
 	// "X-API-Hash" -> sha1 hashed api secret
@@ -279,104 +527,55 @@ func (i *Instance) request(method string, url string, data []byte) ([]byte, erro
 		req.Header.Add("X-API-Hash", hash)
 
 	}
-	// Perform request on a new http client
-	c := http.Client{}
-	r, err := c.Do(req)
-	if err != nil {
-		return make([]byte, 0), err
-	}
+	return req, nil
+}
 
-	// First read body to have it ready in case of errors
-	defer r.Body.Close()
-	buf, err := io.ReadAll(r.Body) // TODO: Limit read size
+// isInstanceHost returns true, if the given URL points to the host of the instance
+func (i *Instance) isInstanceHost(target *url.URL) bool {
+	instance, err := url.Parse(i.URL)
 	if err != nil {
-		return buf, err
+		return false
 	}
-
-	// Check status code
-	if r.StatusCode != 200 {
-		if i.verbose {
-			fmt.Fprintf(os.Stderr, "%s\n", string(buf))
-		}
-		return buf, fmt.Errorf("http status code %d", r.StatusCode)
-	}
-	return buf, nil
+	return strings.EqualFold(instance.Host, target.Host)
 }
 
 /* Query the job overview. params is a map for optional parameters, which will be added to the query.
  * Suitable parameters are `arch`, `distri`, `flavor`, `machine` or `arch`, but everything in this dict will be added to the url
+ * Values of `ids`, `state`, `result` and `groupid` are split on commas to query multiple values. All other values are passed as they are,
+ * use GetOverviewQuery to query multiple values of other parameters
  * Overview returns only the job id and name
  */
 func (i *Instance) GetOverview(testsuite string, params map[string]string) ([]Job, error) {
+	return i.GetOverviewContext(context.Background(), testsuite, params)
+}
+
+func (i *Instance) GetOverviewContext(ctx context.Context, testsuite string, params map[string]string) ([]Job, error) {
 	// Example values:
 	// arch=x86_64
 	// distri=sle
 	// flavor=Server-DVD-Updates
 	// machine=64bit
-
-	// Build URL with all parameters
-	url := fmt.Sprintf("%s/api/v1/jobs/overview", i.URL)
+	q := jobQueryFromParams(params)
 	if testsuite != "" {
-		params["test"] = testsuite
+		q.Test = []string{testsuite}
 	}
-	if len(params) > 0 {
-		url += "?" + mergeParams(params)
-	}
-
-	jobs, err := i.fetchJobs(url)
-	assignInstance(jobs, i)
-	return jobs, err
+	return i.GetOverviewQueryContext(ctx, q)
 }
 
 /* Get only the latest jobs of a certain testsuite. Testsuite must be given here.
  * Additional parameters can be supplied via the params map (See GetOverview for more info about usage of those parameters)
+ * Use GetLatestJobsQuery to query multiple values of parameters that are not split on commas
  */
 func (i *Instance) GetLatestJobs(testsuite string, params map[string]string) ([]Job, error) {
-	// Expected result structure
-	type ResultJob struct {
-		Jobs []Job `json:"jobs"`
-	}
-	var jobs ResultJob
-	if testsuite != "" {
-		params["test"] = testsuite
-	}
-	url := fmt.Sprintf("%s/api/v1/jobs", i.URL)
-	if testsuite != "" {
-		params["test"] = testsuite
-	}
-	url += "?" + mergeParams(params)
-	// Fetch jobs here, as we expect it to be in `jobs`
-	resp, err := i.request("GET", url, nil)
-	if err != nil {
-		return jobs.Jobs, err
-	}
-	err = json.Unmarshal(resp, &jobs)
-	if err != nil {
-		return jobs.Jobs, err
-	}
-
-	// Now, get only the latest job per group_id
-	mapped := make(map[int]Job)
-	for _, job := range jobs.Jobs {
-		job.instance = i
-		job.Remote = i.URL
-		// TODO: Filter job results, if given
+	return i.GetLatestJobsContext(context.Background(), testsuite, params)
+}
 
-		// Only keep newer jobs (by ID) per group
-		if f, ok := mapped[job.GroupID]; ok {
-			if job.ID > f.ID {
-				mapped[job.GroupID] = job
-			}
-		} else {
-			mapped[job.GroupID] = job
-		}
-	}
-	// Make slice from map
-	ret := make([]Job, 0)
-	for _, v := range mapped {
-		ret = append(ret, v)
+func (i *Instance) GetLatestJobsContext(ctx context.Context, testsuite string, params map[string]string) ([]Job, error) {
+	q := jobQueryFromParams(params)
+	if testsuite != "" {
+		q.Test = []string{testsuite}
 	}
-	return ret, nil
+	return i.GetLatestJobsQueryContext(ctx, q)
 }
 
 func (job *Job) applyInstance(i *Instance) {
@@ -387,13 +586,21 @@ func (job *Job) applyInstance(i *Instance) {
 
 // GetJob fetches detailled job information
 func (i *Instance) GetJob(id int64) (Job, error) {
+	return i.GetJobContext(context.Background(), id)
+}
+
+func (i *Instance) GetJobContext(ctx context.Context, id int64) (Job, error) {
 	url := fmt.Sprintf("%s/api/v1/jobs/%d", i.URL, id)
-	job, err := i.fetchJob(url)
+	job, err := i.fetchJob(ctx, url)
 	return job, err
 }
 
 // GetJob fetches detailled information about a list of jobs
 func (i *Instance) GetJobs(ids []int64) ([]Job, error) {
+	return i.GetJobsContext(context.Background(), ids)
+}
+
+func (i *Instance) GetJobsContext(ctx context.Context, ids []int64) ([]Job, error) {
 	if len(ids) == 0 {
 		return make([]Job, 0), nil
 	}
@@ -409,12 +616,16 @@ func (i *Instance) GetJobs(ids []int64) ([]Job, error) {
 			url = fmt.Sprintf("%s&ids=%d", url, id)
 		}
 	}
-	return i.fetchJobsArray(url)
+	return i.fetchJobsArray(ctx, url)
 }
 
 // GetJob fetches detailled information about a list of jobs. Follows cloned jobs, if applicable
 func (inst *Instance) GetJobsFollow(ids []int64) ([]Job, error) {
-	jobs, err := inst.GetJobs(ids)
+	return inst.GetJobsFollowContext(context.Background(), ids)
+}
+
+func (inst *Instance) GetJobsFollowContext(ctx context.Context, ids []int64) ([]Job, error) {
+	jobs, err := inst.GetJobsContext(ctx, ids)
 	if err != nil {
 		return jobs, err
 	}
@@ -423,8 +634,11 @@ func (inst *Instance) GetJobsFollow(ids []int64) ([]Job, error) {
 	// the relation between an original job and it's cloned job is not directly visible.
 	// This means we have to fetch each job individually, so that we can keep track of the jobs origin
 	for i, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			return jobs, err
+		}
 		if job.IsCloned() {
-			job, err := inst.GetJobFollow(job.ID)
+			job, err := inst.GetJobFollowContext(ctx, job.ID)
 			if err != nil {
 				return jobs, err
 			}
@@ -435,8 +649,12 @@ func (inst *Instance) GetJobsFollow(ids []int64) ([]Job, error) {
 }
 
 func (i *Instance) DeleteJob(id int64) error {
+	return i.DeleteJobContext(context.Background(), id)
+}
+
+func (i *Instance) DeleteJobContext(ctx context.Context, id int64) error {
 	url := fmt.Sprintf("%s/api/v1/jobs/%d", i.URL, id)
-	buf, err := i.delete(url, nil)
+	buf, err := i.delete(ctx, url, nil)
 	if i.verbose {
 		fmt.Fprintf(os.Stderr, "%s\n", buf)
 	}
@@ -445,9 +663,16 @@ func (i *Instance) DeleteJob(id int64) error {
 
 // GetJob fetches detailled job information and follows the job, if it contains a CloneID
 func (inst *Instance) GetJobFollow(id int64) (Job, error) {
+	return inst.GetJobFollowContext(context.Background(), id)
+}
+
+func (inst *Instance) GetJobFollowContext(ctx context.Context, id int64) (Job, error) {
 	for recursion := 0; recursion < inst.maxRecursions; recursion++ {
+		if err := ctx.Err(); err != nil {
+			return Job{}, err
+		}
 		url := fmt.Sprintf("%s/api/v1/jobs/%d", inst.URL, id)
-		job, err := inst.fetchJob(url)
+		job, err := inst.fetchJob(ctx, url)
 		if err != nil {
 			return job, err
 		}
@@ -462,33 +687,53 @@ func (inst *Instance) GetJobFollow(id int64) (Job, error) {
 
 // GetJobState uses the (currently experimental) API call to quickly fetch a job state
 func (i *Instance) GetJobState(id int64) (JobState, error) {
+	return i.GetJobStateContext(context.Background(), id)
+}
+
+func (i *Instance) GetJobStateContext(ctx context.Context, id int64) (JobState, error) {
 	url := fmt.Sprintf("%s/api/v1//experimental/jobs/%d/status", i.URL, id)
-	return i.fetchJobState(url)
+	state, err := i.fetchJobState(ctx, url)
+	if state.ID == 0 {
+		state.ID = id
+	}
+	return state, err
 }
 
 func (i *Instance) GetJobGroups() ([]JobGroup, error) {
+	return i.GetJobGroupsContext(context.Background())
+}
+
+func (i *Instance) GetJobGroupsContext(ctx context.Context) ([]JobGroup, error) {
 	url := fmt.Sprintf("%s/api/v1/job_groups", i.URL)
-	return i.fetchJobGroups(url)
+	return i.fetchJobGroups(ctx, url)
 }
 
 func (i *Instance) GetJobGroup(id int) (JobGroup, error) {
+	return i.GetJobGroupContext(context.Background(), id)
+}
+
+func (i *Instance) GetJobGroupContext(ctx context.Context, id int) (JobGroup, error) {
 	url := fmt.Sprintf("%s/api/v1/job_groups/%d", i.URL, id)
-	groups, err := i.fetchJobGroups(url)
+	groups, err := i.fetchJobGroups(ctx, url)
 	if err != nil {
 		return JobGroup{}, err
 	}
 	if len(groups) == 0 {
-		return JobGroup{}, fmt.Errorf("not found")
+		return JobGroup{}, fmt.Errorf("job group %d: %w", id, ErrNotFound)
 	}
 	return groups[0], nil
 }
 
 func (i *Instance) PostJobGroup(jobgroup JobGroup) (JobGroup, error) {
+	return i.PostJobGroupContext(context.Background(), jobgroup)
+}
+
+func (i *Instance) PostJobGroupContext(ctx context.Context, jobgroup JobGroup) (JobGroup, error) {
 	rurl := fmt.Sprintf("%s/api/v1/job_groups", i.URL)
 	//if jobgroup.ID > 0 {
 	//	rurl = fmt.Sprintf("%s/api/v1/job_groups/%d", i.URL, jobgroup.ID)
 	//}
-	buf, err := i.post(rurl, []byte(jobgroup.encodeWWW()))
+	buf, err := i.post(ctx, rurl, []byte(jobgroup.encodeWWW()))
 	if err != nil {
 		return jobgroup, err
 	}
@@ -497,28 +742,40 @@ func (i *Instance) PostJobGroup(jobgroup JobGroup) (JobGroup, error) {
 }
 
 func (i *Instance) GetParentJobGroups() ([]JobGroup, error) {
+	return i.GetParentJobGroupsContext(context.Background())
+}
+
+func (i *Instance) GetParentJobGroupsContext(ctx context.Context) ([]JobGroup, error) {
 	url := fmt.Sprintf("%s/api/v1/parent_groups", i.URL)
-	return i.fetchJobGroups(url)
+	return i.fetchJobGroups(ctx, url)
 }
 
 func (i *Instance) GetParentJobGroup(id int) (JobGroup, error) {
+	return i.GetParentJobGroupContext(context.Background(), id)
+}
+
+func (i *Instance) GetParentJobGroupContext(ctx context.Context, id int) (JobGroup, error) {
 	url := fmt.Sprintf("%s/api/v1/parent_groups/%d", i.URL, id)
-	groups, err := i.fetchJobGroups(url)
+	groups, err := i.fetchJobGroups(ctx, url)
 	if err != nil {
 		return JobGroup{}, err
 	}
 	if len(groups) == 0 {
-		return JobGroup{}, fmt.Errorf("not found")
+		return JobGroup{}, fmt.Errorf("parent job group %d: %w", id, ErrNotFound)
 	}
 	return groups[0], nil
 }
 
 func (i *Instance) PostParentJobGroup(jobgroup JobGroup) (JobGroup, error) {
+	return i.PostParentJobGroupContext(context.Background(), jobgroup)
+}
+
+func (i *Instance) PostParentJobGroupContext(ctx context.Context, jobgroup JobGroup) (JobGroup, error) {
 	rurl := fmt.Sprintf("%s/api/v1/parent_groups", i.URL)
 	//if jobgroup.ID > 0 {
 	//	rurl = fmt.Sprintf("%s/api/v1/parent_groups/%d", i.URL, jobgroup.ID)
 	//}
-	buf, err := i.post(rurl, []byte(jobgroup.encodeWWW()))
+	buf, err := i.post(ctx, rurl, []byte(jobgroup.encodeWWW()))
 	if err != nil {
 		return jobgroup, err
 	}
@@ -527,15 +784,19 @@ func (i *Instance) PostParentJobGroup(jobgroup JobGroup) (JobGroup, error) {
 }
 
 func (i *Instance) GetWorkers() ([]Worker, error) {
+	return i.GetWorkersContext(context.Background())
+}
+
+func (i *Instance) GetWorkersContext(ctx context.Context) ([]Worker, error) {
 	url := fmt.Sprintf("%s/api/v1/workers", i.URL)
-	return i.fetchWorkers(url)
+	return i.fetchWorkers(ctx, url)
 }
 
 // fetchJobs fetches the given url and returns all jobs returned by it (as direct array)
-func (inst *Instance) fetchJobs(url string) ([]Job, error) {
+func (inst *Instance) fetchJobs(ctx context.Context, url string) ([]Job, error) {
 	jobs := make([]Job, 0)
 
-	resp, err := inst.get(url, nil)
+	resp, err := inst.get(ctx, url, nil)
 	if err != nil {
 		return jobs, err
 	}
@@ -548,12 +809,12 @@ func (inst *Instance) fetchJobs(url string) ([]Job, error) {
 }
 
 // fetchJobs fetches the given url and returns all jobs, It expects the jobs to be within the "jobs" dict of the result
-func (inst *Instance) fetchJobsArray(url string) ([]Job, error) {
+func (inst *Instance) fetchJobsArray(ctx context.Context, url string) ([]Job, error) {
 	type ResultJob struct { // Expected result structure
 		Jobs []Job `json:"jobs"`
 	}
 	var ret ResultJob
-	resp, err := inst.get(url, nil)
+	resp, err := inst.get(ctx, url, nil)
 	if err != nil {
 		return make([]Job, 0), err
 	}
@@ -565,20 +826,19 @@ func (inst *Instance) fetchJobsArray(url string) ([]Job, error) {
 	return ret.Jobs, err
 }
 
-func (inst *Instance) fetchJobGroups(url string) ([]JobGroup, error) {
+func (inst *Instance) fetchJobGroups(ctx context.Context, url string) ([]JobGroup, error) {
 	jobs := make([]JobGroup, 0)
 
-	resp, err := inst.get(url, nil)
+	resp, err := inst.get(ctx, url, nil)
 	if err != nil {
 		return jobs, err
 	}
-	// TODO: Sometimes SizeLimit is returned as string but it should be an int. Fix this.
 	err = json.Unmarshal(resp, &jobs)
 	return jobs, err
 }
 
-func (i *Instance) fetchWorkers(url string) ([]Worker, error) {
-	resp, err := i.get(url, nil)
+func (i *Instance) fetchWorkers(ctx context.Context, url string) ([]Worker, error) {
+	resp, err := i.get(ctx, url, nil)
 	if err != nil {
 		return make([]Worker, 0), err
 	}
@@ -591,8 +851,20 @@ func (i *Instance) fetchWorkers(url string) ([]Worker, error) {
 	return make([]Worker, 0), nil
 }
 
-func (i *Instance) fetchJobTemplates(url string) ([]JobTemplate, error) {
-	resp, err := i.get(url, nil)
+func (i *Instance) fetchWorker(ctx context.Context, url string) (Worker, error) {
+	var worker struct { // Expected result structure
+		Worker Worker `json:"worker"`
+	}
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return worker.Worker, err
+	}
+	err = json.Unmarshal(resp, &worker)
+	return worker.Worker, err
+}
+
+func (i *Instance) fetchJobTemplates(ctx context.Context, url string) ([]JobTemplate, error) {
+	resp, err := i.get(ctx, url, nil)
 	if err != nil {
 		return make([]JobTemplate, 0), err
 	}
@@ -605,8 +877,8 @@ func (i *Instance) fetchJobTemplates(url string) ([]JobTemplate, error) {
 	return make([]JobTemplate, 0), nil
 }
 
-func (i *Instance) fetchMachines(url string) ([]Machine, error) {
-	resp, err := i.get(url, nil)
+func (i *Instance) fetchMachines(ctx context.Context, url string) ([]Machine, error) {
+	resp, err := i.get(ctx, url, nil)
 	if err != nil {
 		return make([]Machine, 0), err
 	}
@@ -626,24 +898,46 @@ func (i *Instance) fetchMachines(url string) ([]Machine, error) {
 	return make([]Machine, 0), nil
 }
 
-func (inst *Instance) fetchJob(url string) (Job, error) {
+func (i *Instance) fetchTestSuites(ctx context.Context, url string) ([]TestSuite, error) {
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return make([]TestSuite, 0), err
+	}
+	// test suites come as a "TestSuites:[...]" dict
+	suites := make(map[string][]testSuiteSettings, 0)
+	if err := json.Unmarshal(resp, &suites); err != nil {
+		return make([]TestSuite, 0), err
+	}
+	if suites, ok := suites["TestSuites"]; ok {
+		ret := make([]TestSuite, 0)
+		for _, suite := range suites {
+			ret = append(ret, suite.toTestSuite())
+		}
+		return ret, nil
+	}
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(resp))
+	}
+	return make([]TestSuite, 0), ErrInvalidResponse
+}
+
+func (inst *Instance) fetchJob(ctx context.Context, url string) (Job, error) {
 	type ResultJob struct { // Expected result structure
 		Job Job `json:"job"`
 	}
 	var job ResultJob
-	resp, err := inst.get(url, nil)
+	resp, err := inst.get(ctx, url, nil)
 	if err != nil {
 		return job.Job, err
 	}
-	// TODO: Sometimes SizeLimit is returned as string but it should be an int. Fix this.
 	err = json.Unmarshal(resp, &job)
 	job.Job.applyInstance(inst)
 	return job.Job, err
 }
 
-func (i *Instance) fetchJobState(url string) (JobState, error) {
+func (i *Instance) fetchJobState(ctx context.Context, url string) (JobState, error) {
 	var state JobState
-	resp, err := i.get(url, nil)
+	resp, err := i.get(ctx, url, nil)
 	if err != nil {
 		return state, err
 	}
@@ -651,27 +945,16 @@ func (i *Instance) fetchJobState(url string) (JobState, error) {
 	return state, err
 }
 
-/* merge given parameter string to URL parameters */
-func mergeParams(params map[string]string) string {
-	if len(params) == 0 {
-		return ""
-	}
-	vals := make([]string, 0)
-	for k, arg := range params {
-		// openQA supports parameter arrays by passing them multiple times. We do this by splitting commas
-		for _, v := range strings.Split(arg, ",") {
-			vals = append(vals, fmt.Sprintf("%s=%s", k, v))
-		}
-	}
-	return strings.Join(vals, "&")
-}
-
 /*
  * Fetch the given child jobs. Use with j.Children.Chained, j.Children.DirectlyChained and j.Children.Parallel
  * if follow is set to true, the method will return the cloned job instead of the original one, if present
  */
 func (j *Job) FetchChildren(ids []int64, follow bool) ([]Job, error) {
-	children, err := j.instance.GetJobs(ids)
+	return j.FetchChildrenContext(context.Background(), ids, follow)
+}
+
+func (j *Job) FetchChildrenContext(ctx context.Context, ids []int64, follow bool) ([]Job, error) {
+	children, err := j.instance.GetJobsContext(ctx, ids)
 	if err != nil {
 		return children, err
 	}
@@ -679,7 +962,7 @@ func (j *Job) FetchChildren(ids []int64, follow bool) ([]Job, error) {
 		for i, job := range children {
 			// Fetch cloned job, if present
 			if job.CloneID != 0 && job.CloneID != job.ID {
-				job, err := j.instance.GetJobFollow(job.ID)
+				job, err := j.instance.GetJobFollowContext(ctx, job.ID)
 				if err != nil {
 					return children, err
 				}
@@ -694,22 +977,34 @@ func (j *Job) FetchChildren(ids []int64, follow bool) ([]Job, error) {
  * follow determines if we should follow the given children, i.e. get their cloned jobs instead of the original ones if present
  */
 func (j *Job) FetchAllChildren(follow bool) ([]Job, error) {
+	return j.FetchAllChildrenContext(context.Background(), follow)
+}
+
+func (j *Job) FetchAllChildrenContext(ctx context.Context, follow bool) ([]Job, error) {
 	children := make([]int64, 0)
 	children = append(children, j.Children.Chained...)
 	children = append(children, j.Children.DirectlyChained...)
 	children = append(children, j.Children.Parallel...)
-	return j.FetchChildren(children, follow)
+	return j.FetchChildrenContext(ctx, children, follow)
 }
 
 func (i *Instance) GetJobTemplates() ([]JobTemplate, error) {
+	return i.GetJobTemplatesContext(context.Background())
+}
+
+func (i *Instance) GetJobTemplatesContext(ctx context.Context) ([]JobTemplate, error) {
 	url := fmt.Sprintf("%s/api/v1/job_templates", i.URL)
-	return i.fetchJobTemplates(url)
+	return i.fetchJobTemplates(ctx, url)
 }
 
 func (instance *Instance) GetJobGroupJobs(id int) ([]int64, error) {
+	return instance.GetJobGroupJobsContext(context.Background(), id)
+}
+
+func (instance *Instance) GetJobGroupJobsContext(ctx context.Context, id int) ([]int64, error) {
 	ids := make([]int64, 0)
 	url := fmt.Sprintf("%s/api/v1/job_groups/%d/jobs", instance.URL, id)
-	buf, err := instance.get(url, nil)
+	buf, err := instance.get(ctx, url, nil)
 	if err != nil {
 		return ids, err
 	}
@@ -723,16 +1018,20 @@ func (instance *Instance) GetJobGroupJobs(id int) ([]int64, error) {
 	if ids, ok := obj["ids"]; ok {
 		return ids, nil
 	} else {
-		return ids, fmt.Errorf("invalid response")
+		return ids, ErrInvalidResponse
 	}
 }
 
 func (i *Instance) DeleteJobGroupJobs(id int) error {
-	if jobs, err := i.GetJobGroupJobs(id); err != nil {
+	return i.DeleteJobGroupJobsContext(context.Background(), id)
+}
+
+func (i *Instance) DeleteJobGroupJobsContext(ctx context.Context, id int) error {
+	if jobs, err := i.GetJobGroupJobsContext(ctx, id); err != nil {
 		return err
 	} else {
 		for _, id := range jobs {
-			if err := i.DeleteJob(id); err != nil {
+			if err := i.DeleteJobContext(ctx, id); err != nil {
 				return err
 			}
 		}
@@ -741,16 +1040,24 @@ func (i *Instance) DeleteJobGroupJobs(id int) error {
 }
 
 func (i *Instance) DeleteJobGroup(id int) error {
+	return i.DeleteJobGroupContext(context.Background(), id)
+}
+
+func (i *Instance) DeleteJobGroupContext(ctx context.Context, id int) error {
 	url := fmt.Sprintf("%s/api/v1/job_groups/%d", i.URL, id)
-	buf, err := i.delete(url, nil)
+	buf, err := i.delete(ctx, url, nil)
 	if i.verbose {
 		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
 	}
 	return err
 }
 func (i *Instance) DeleteJobTemplate(id int) error {
+	return i.DeleteJobTemplateContext(context.Background(), id)
+}
+
+func (i *Instance) DeleteJobTemplateContext(ctx context.Context, id int) error {
 	url := fmt.Sprintf("%s/api/v1/job_templates/%d", i.URL, id)
-	buf, err := i.delete(url, nil)
+	buf, err := i.delete(ctx, url, nil)
 	if i.verbose {
 		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
 	}
@@ -758,37 +1065,57 @@ func (i *Instance) DeleteJobTemplate(id int) error {
 }
 
 func (i *Instance) GetJobTemplate(id int) (JobTemplate, error) {
+	return i.GetJobTemplateContext(context.Background(), id)
+}
+
+func (i *Instance) GetJobTemplateContext(ctx context.Context, id int) (JobTemplate, error) {
 	url := fmt.Sprintf("%s/api/v1/job_templates/%d", i.URL, id)
-	templates, err := i.fetchJobTemplates(url)
+	templates, err := i.fetchJobTemplates(ctx, url)
 	if err != nil {
 		return JobTemplate{}, err
 	}
 	if len(templates) == 0 {
-		return JobTemplate{}, fmt.Errorf("not found")
+		return JobTemplate{}, fmt.Errorf("job template %d: %w", id, ErrNotFound)
 	} else {
 		return templates[0], nil
 	}
 }
 
 func (i *Instance) GetJobTemplateYAML(id int) (string, error) {
+	return i.GetJobTemplateYAMLContext(context.Background(), id)
+}
+
+func (i *Instance) GetJobTemplateYAMLContext(ctx context.Context, id int) (string, error) {
 	url := fmt.Sprintf("%s/api/v1/job_templates_scheduling/%d", i.URL, id)
-	buf, err := i.get(url, nil)
+	buf, err := i.get(ctx, url, nil)
 	return string(buf), err
 }
 func (i *Instance) PostJobTemplateYAML(id int, yaml string) error {
+	return i.PostJobTemplateYAMLContext(context.Background(), id, yaml)
+}
+
+func (i *Instance) PostJobTemplateYAMLContext(ctx context.Context, id int, yaml string) error {
 	url := fmt.Sprintf("%s/api/v1/job_templates_scheduling/%d", i.URL, id)
-	_, err := i.post(url, []byte(yaml))
+	_, err := i.post(ctx, url, []byte(yaml))
 	return err
 }
 
 func (i *Instance) GetMachines() ([]Machine, error) {
+	return i.GetMachinesContext(context.Background())
+}
+
+func (i *Instance) GetMachinesContext(ctx context.Context) ([]Machine, error) {
 	url := fmt.Sprintf("%s/api/v1/machines", i.URL)
-	return i.fetchMachines(url)
+	return i.fetchMachines(ctx, url)
 }
 
 func (i *Instance) GetMachine(id int) (Machine, error) {
+	return i.GetMachineContext(context.Background(), id)
+}
+
+func (i *Instance) GetMachineContext(ctx context.Context, id int) (Machine, error) {
 	url := fmt.Sprintf("%s/api/v1/machines/%d", i.URL, id)
-	if machines, err := i.fetchMachines(url); err != nil {
+	if machines, err := i.fetchMachines(ctx, url); err != nil {
 		return Machine{}, err
 	} else {
 		if len(machines) > 0 {
@@ -800,6 +1127,10 @@ func (i *Instance) GetMachine(id int) (Machine, error) {
 }
 
 func (i *Instance) PostMachine(machine Machine) (Machine, error) {
+	return i.PostMachineContext(context.Background(), machine)
+}
+
+func (i *Instance) PostMachineContext(ctx context.Context, machine Machine) (Machine, error) {
 	if i.apikey == "" || i.apisecret == "" {
 		return Machine{}, fmt.Errorf("API key or secret not set")
 	}
@@ -830,7 +1161,7 @@ func (i *Instance) PostMachine(machine Machine) (Machine, error) {
 	if err != nil {
 		return Machine{}, err
 	}
-	if buf, err := i.post(rurl, buf); err != nil {
+	if buf, err := i.post(ctx, rurl, buf); err != nil {
 		return Machine{}, err
 	} else {
 		err = json.Unmarshal(buf, &machine)
@@ -839,12 +1170,16 @@ func (i *Instance) PostMachine(machine Machine) (Machine, error) {
 }
 
 func (i *Instance) DeleteMachine(id int) error {
+	return i.DeleteMachineContext(context.Background(), id)
+}
+
+func (i *Instance) DeleteMachineContext(ctx context.Context, id int) error {
 	if i.apikey == "" || i.apisecret == "" {
 		return fmt.Errorf("API key or secret not set")
 	}
 
 	rurl := fmt.Sprintf("%s/api/v1/machines/%d", i.URL, id)
-	buf, err := i.delete(rurl, nil)
+	buf, err := i.delete(ctx, rurl, nil)
 	if i.verbose {
 		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
 	}
@@ -855,10 +1190,101 @@ func (i *Instance) DeleteMachine(id int) error {
 	}
 }
 
+func (i *Instance) GetTestSuites() ([]TestSuite, error) {
+	return i.GetTestSuitesContext(context.Background())
+}
+
+func (i *Instance) GetTestSuitesContext(ctx context.Context) ([]TestSuite, error) {
+	rurl := fmt.Sprintf("%s/api/v1/test_suites", i.URL)
+	return i.fetchTestSuites(ctx, rurl)
+}
+
+func (i *Instance) GetTestSuite(id int) (TestSuite, error) {
+	return i.GetTestSuiteContext(context.Background(), id)
+}
+
+func (i *Instance) GetTestSuiteContext(ctx context.Context, id int) (TestSuite, error) {
+	rurl := fmt.Sprintf("%s/api/v1/test_suites/%d", i.URL, id)
+	suites, err := i.fetchTestSuites(ctx, rurl)
+	if err != nil {
+		return TestSuite{}, err
+	}
+	if len(suites) == 0 {
+		return TestSuite{}, fmt.Errorf("test suite %d: %w", id, ErrNotFound)
+	}
+	return suites[0], nil
+}
+
+/* Create a new test suite or update an existing one, if testsuite.ID is set */
+func (i *Instance) PostTestSuite(testsuite TestSuite) (TestSuite, error) {
+	return i.PostTestSuiteContext(context.Background(), testsuite)
+}
+
+func (i *Instance) PostTestSuiteContext(ctx context.Context, testsuite TestSuite) (TestSuite, error) {
+	if i.apikey == "" || i.apisecret == "" {
+		return TestSuite{}, fmt.Errorf("API key or secret not set")
+	}
+
+	var rurl string
+	if testsuite.ID == 0 {
+		rurl = fmt.Sprintf("%s/api/v1/test_suites", i.URL)
+	} else {
+		rurl = fmt.Sprintf("%s/api/v1/test_suites/%d", i.URL, testsuite.ID)
+	}
+	wsuite := testSuiteSettings{ID: testsuite.ID, Name: testsuite.Name, Description: testsuite.Description}
+	wsuite.CopySettingsFrom(testsuite)
+	var buf []byte
+	var err error
+	if testsuite.ID == 0 {
+		buf, err = i.post(ctx, rurl, []byte(wsuite.encodeParams()))
+	} else {
+		// Updates are done via PUT
+		buf, err = i.put(ctx, rurl, []byte(wsuite.encodeParams()))
+	}
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	if err != nil {
+		return TestSuite{}, err
+	}
+	// Result: {"id":1}
+	var obj struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(buf, &obj); err != nil {
+		return testsuite, err
+	}
+	if obj.ID > 0 {
+		testsuite.ID = obj.ID
+	}
+	return testsuite, nil
+}
+
+func (i *Instance) DeleteTestSuite(id int) error {
+	return i.DeleteTestSuiteContext(context.Background(), id)
+}
+
+func (i *Instance) DeleteTestSuiteContext(ctx context.Context, id int) error {
+	if i.apikey == "" || i.apisecret == "" {
+		return fmt.Errorf("API key or secret not set")
+	}
+
+	rurl := fmt.Sprintf("%s/api/v1/test_suites/%d", i.URL, id)
+	buf, err := i.delete(ctx, rurl, nil)
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	return err
+}
+
 func (i *Instance) GetProducts() ([]Product, error) {
+	return i.GetProductsContext(context.Background())
+}
+
+func (i *Instance) GetProductsContext(ctx context.Context) ([]Product, error) {
 	products := make([]Product, 0)
 	rurl := fmt.Sprintf("%s/api/v1/products", i.URL)
-	buf, err := i.get(rurl, nil)
+	buf, err := i.get(ctx, rurl, nil)
 	if err != nil {
 		return products, err
 	}
@@ -876,12 +1302,16 @@ func (i *Instance) GetProducts() ([]Product, error) {
 	if i.verbose {
 		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
 	}
-	return products, fmt.Errorf("invalid response")
+	return products, ErrInvalidResponse
 }
 
 func (i *Instance) GetProduct(id int) (Product, error) {
+	return i.GetProductContext(context.Background(), id)
+}
+
+func (i *Instance) GetProductContext(ctx context.Context, id int) (Product, error) {
 	rurl := fmt.Sprintf("%s/api/v1/products/%d", i.URL, id)
-	buf, err := i.get(rurl, nil)
+	buf, err := i.get(ctx, rurl, nil)
 	if err != nil {
 		return Product{}, err
 	}
@@ -891,18 +1321,22 @@ func (i *Instance) GetProduct(id int) (Product, error) {
 	}
 	if products, ok := obj["Products"]; ok {
 		if len(products) == 0 {
-			return Product{}, fmt.Errorf("not found")
+			return Product{}, fmt.Errorf("product %d: %w", id, ErrNotFound)
 		}
 		return products[0].toProduct(), nil
 	} else {
 		if i.verbose {
 			fmt.Fprintf(os.Stderr, "%s\n", string(buf))
 		}
-		return Product{}, fmt.Errorf("invalid response")
+		return Product{}, ErrInvalidResponse
 	}
 }
 
 func (i *Instance) PostProduct(product Product) (Product, error) {
+	return i.PostProductContext(context.Background(), product)
+}
+
+func (i *Instance) PostProductContext(ctx context.Context, product Product) (Product, error) {
 	rurl := ""
 	if product.ID == 0 {
 		rurl = fmt.Sprintf("%s/api/v1/products", i.URL)
@@ -916,7 +1350,7 @@ func (i *Instance) PostProduct(product Product) (Product, error) {
 	if i.verbose {
 		fmt.Fprintf(os.Stderr, "%s\n", data)
 	}
-	buf, err := i.post(rurl, data)
+	buf, err := i.post(ctx, rurl, data)
 	if i.verbose {
 		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
 	}
@@ -929,15 +1363,10 @@ func (i *Instance) PostProduct(product Product) (Product, error) {
 
 /* Fetch comments for a given job */
 func (i *Instance) GetComments(job int64) ([]Comment, error) {
-	ret := make([]Comment, 0)
+	return i.GetCommentsContext(context.Background(), job)
+}
+
+func (i *Instance) GetCommentsContext(ctx context.Context, job int64) ([]Comment, error) {
 	rurl := fmt.Sprintf("%s/api/v1/jobs/%d/comments", i.URL, job)
-	buf, err := i.get(rurl, nil)
-	if i.verbose {
-		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
-	}
-	if err != nil {
-		return ret, err
-	}
-	err = json.Unmarshal(buf, &ret)
-	return ret, err
+	return i.fetchComments(ctx, rurl)
 }
@@ -6,11 +6,28 @@ package gopenqa
  */
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
+	amqp "github.com/rabbitmq/amqp091-go"
 	"gotest.tools/assert"
 )
 
@@ -19,27 +36,116 @@ var instance Instance
 const COMMENT_TEST_JOB_ID = 5830
 
 /* Test server http - Serves directories in test/ */
-func setupTestServer() {
+func newTestMux() *http.ServeMux {
+	mux := http.NewServeMux()
 	fs := http.FileServer(http.Dir("./test"))
-	http.Handle("/api/v1/", http.StripPrefix("/api/v1/", fs))
-	go func() {
-		if err := http.ListenAndServe(":8421", nil); err != nil {
-			panic(err)
-		}
-	}()
+	mux.Handle("/api/v1/", http.StripPrefix("/api/v1/", fs))
+	return mux
 }
 
 func TestMain(m *testing.M) {
 	// Testserver initialization
-	setupTestServer()
+	server := httptest.NewServer(newTestMux())
 	log.Println("http server setup complete")
-	instance = CreateInstance("http://localhost:8421")
+	instance = CreateInstance(server.URL)
 
 	// Run tests
 	ret := m.Run()
+	server.Close()
 	os.Exit(ret)
 }
 
+/* Request as received by a fixtureServer */
+type testRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Form   url.Values // Posted form values
+	Header http.Header
+}
+
+/* Test server for routes that are not covered by the test directory, e.g. POST requests
+ * Every test gets its own server. Routes without a handler are served from the test directory
+ * Received requests of registered routes are recorded, so that the test can check them. Never assert within a handler, as it runs in the goroutine of the http server
+ */
+type fixtureServer struct {
+	*httptest.Server
+	mux      *http.ServeMux
+	mutex    sync.Mutex
+	requests []testRequest
+}
+
+func newFixtureServer(t *testing.T) *fixtureServer {
+	s := &fixtureServer{mux: newTestMux()}
+	s.Server = httptest.NewServer(s.mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Instance returns an instance that uses the routes of this server
+func (s *fixtureServer) Instance() Instance {
+	return CreateInstance(s.URL)
+}
+
+// Handle responds to requests of the given route with the given file of the test directory
+func (s *fixtureServer) Handle(route string, fixture string) {
+	s.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(w, r, fixture)
+	})
+}
+
+// HandleFunc registers the handler for the given route. Requests are recorded before they are passed to the handler
+func (s *fixtureServer) HandleFunc(route string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		req := testRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Form: r.PostForm, Header: r.Header.Clone()}
+		s.mutex.Lock()
+		s.requests = append(s.requests, req)
+		s.mutex.Unlock()
+		handler(w, r)
+	})
+}
+
+// Requests returns the recorded requests of the given path, or all recorded requests if path is empty
+func (s *fixtureServer) Requests(path string) []testRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ret := make([]testRequest, 0)
+	for _, req := range s.requests {
+		if path == "" || req.Path == path {
+			ret = append(ret, req)
+		}
+	}
+	return ret
+}
+
+// serveFixture responds with the given file of the test directory
+func serveFixture(w http.ResponseWriter, r *http.Request, fixture string) {
+	http.ServeFile(w, r, filepath.Join("test", fixture))
+}
+
+func TestHTTPClient(t *testing.T) {
+	// Use a custom transport and ensure it is used for the requests
+	transport := &countingTransport{}
+	inst := CreateInstanceWithClient(instance.URL, &http.Client{Transport: transport})
+	_, err := inst.GetJob(5991)
+	assert.NilError(t, err)
+	assert.Equal(t, transport.requests, 1)
+	inst.SetTransport(transport)
+	_, err = inst.GetJob(5991)
+	assert.NilError(t, err)
+	assert.Equal(t, transport.requests, 2)
+}
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
 func TestOverview(t *testing.T) {
 	jobs, err := instance.GetOverview("test", EmptyParams())
 	if err != nil {
@@ -159,3 +265,998 @@ func TestProduct(t *testing.T) {
 	assert.Equal(t, products[2].Settings["BOOT_HDD_IMAGE"], "1")
 	assert.Equal(t, products[2].Settings["HDD_1"], "openSUSE-1-aarch64-DVD.iso")
 }
+
+func TestContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := instance.GetJobContext(ctx, 5991)
+	assert.Assert(t, errors.Is(err, context.Canceled))
+	_, err = instance.GetJobsFollowContext(ctx, []int64{5990, 5991})
+	assert.Assert(t, errors.Is(err, context.Canceled))
+
+	// Cancelling aborts a request in flight
+	server := newFixtureServer(t)
+	started := make(chan bool)
+	server.HandleFunc("/api/v1/jobs/5991", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+	inst := server.Instance()
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err = inst.GetJobContext(ctx, 5991)
+	assert.Assert(t, errors.Is(err, context.Canceled))
+
+	// Cancelling stops following the clones. Job 100 is cloned as job 101
+	ctx, cancel = context.WithCancel(context.Background())
+	server.HandleFunc("/api/v1/jobs/100", func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		serveFixture(w, r, "wait/100")
+	})
+	server.Handle("/api/v1/jobs/101", "wait/101")
+	_, err = inst.GetJobFollowContext(ctx, 100)
+	assert.Assert(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, len(server.Requests("/api/v1/jobs/100")), 1)
+	assert.Equal(t, len(server.Requests("/api/v1/jobs/101")), 0)
+}
+
+func TestAPIError(t *testing.T) {
+	_, err := instance.GetJob(1)
+	assert.Assert(t, errors.Is(err, ErrNotFound))
+	assert.Assert(t, !errors.Is(err, ErrUnauthorized))
+	var apiErr *APIError
+	assert.Assert(t, errors.As(err, &apiErr))
+	assert.Equal(t, apiErr.StatusCode, 404)
+	assert.Equal(t, apiErr.Method, "GET")
+	assert.Equal(t, apiErr.URL, instance.URL+"/api/v1/jobs/1")
+	// openQA error messages are decoded from the body
+	apiErr = newAPIError("POST", "http://localhost/api/v1/jobs", 403, []byte(`{"error":"no api key","error_status":403}`))
+	assert.Equal(t, apiErr.Message, "no api key")
+	assert.Assert(t, errors.Is(apiErr, ErrForbidden))
+}
+
+func TestRetry(t *testing.T) {
+	// Handler that fails the first two requests with 503
+	flaky := newFixtureServer(t)
+	flaky.HandleFunc("/api/v1/jobs/5991", func(w http.ResponseWriter, r *http.Request) {
+		if len(flaky.Requests("")) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		serveFixture(w, r, "jobs/5991")
+	})
+	inst := flaky.Instance()
+	_, err := inst.GetJob(5991)
+	assert.Assert(t, err != nil)
+	assert.Equal(t, len(flaky.Requests("")), 1)
+	// Don't wait between the attempts
+	policy := DefaultRetryPolicy()
+	delays := make([]time.Duration, 0)
+	retrySleep = func(ctx context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return nil
+	}
+	defer func() { retrySleep = sleepContext }()
+	inst.SetRetryPolicy(policy)
+	job, err := inst.GetJob(5991)
+	assert.NilError(t, err)
+	assert.Equal(t, job.ID, int64(5991))
+	assert.Equal(t, len(flaky.Requests("")), 3)
+	assert.Equal(t, len(delays), 1)
+	// Other requests are not blocked during the backoff
+	waiting, resume := make(chan bool), make(chan bool)
+	retrySleep = func(ctx context.Context, delay time.Duration) error {
+		close(waiting)
+		<-resume
+		return nil
+	}
+	var once sync.Once
+	backoff := newFixtureServer(t)
+	backoff.HandleFunc("/api/v1/jobs/5991", func(w http.ResponseWriter, r *http.Request) {
+		first := false
+		once.Do(func() { first = true })
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		serveFixture(w, r, "jobs/5991")
+	})
+	inst = backoff.Instance()
+	inst.SetRetryPolicy(policy)
+	done := make(chan error, 1)
+	go func() {
+		_, err := inst.GetJob(5991)
+		done <- err
+	}()
+	<-waiting
+	comments := make(chan error, 1)
+	go func() {
+		_, err := inst.GetComments(COMMENT_TEST_JOB_ID)
+		comments <- err
+	}()
+	select {
+	case err := <-comments:
+		assert.NilError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("request blocked by the backoff of another request")
+	}
+	close(resume)
+	assert.NilError(t, <-done)
+	assert.Equal(t, len(backoff.Requests("/api/v1/jobs/5991")), 2)
+	// Only transient connection errors are retried
+	ctx := context.Background()
+	assert.Assert(t, policy.retryable(ctx, nil, &url.Error{Op: "Get", URL: "/", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}))
+	assert.Assert(t, policy.retryable(ctx, nil, &url.Error{Op: "Get", URL: "/", Err: io.ErrUnexpectedEOF}))
+	assert.Assert(t, policy.retryable(ctx, nil, &url.Error{Op: "Get", URL: "/", Err: &net.DNSError{IsTimeout: true}}))
+	assert.Assert(t, !policy.retryable(ctx, nil, &url.Error{Op: "Get", URL: "/", Err: &net.DNSError{IsNotFound: true}}))
+	assert.Assert(t, !policy.retryable(ctx, nil, &url.Error{Op: "Get", URL: "/", Err: x509.UnknownAuthorityError{}}))
+	// POST requests are not retried by default
+	assert.Equal(t, policy.attempts("POST"), 1)
+	assert.Equal(t, parseRetryAfter("120"), 2*time.Minute)
+	// Delays requested by the server are limited
+	policy.Jitter = 0
+	response := &http.Response{Header: http.Header{"Retry-After": []string{"86400"}}}
+	assert.Equal(t, policy.backoff(1, response), 5*time.Minute)
+	policy.MaxRetryAfter = 0
+	assert.Equal(t, policy.backoff(1, response), 30*time.Second)
+}
+
+func TestRateLimit(t *testing.T) {
+	// Handler that keeps track of the maximum number of concurrent requests
+	var current, max int32
+	server := newFixtureServer(t)
+	server.HandleFunc("/api/v1/jobs/5991", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		serveFixture(w, r, "jobs/5991")
+	})
+	inst := server.Instance()
+	inst.SetAllowParallel(true)
+	inst.SetRateLimit(RateLimit{RequestsPerSecond: 200, Burst: 2, MaxInFlight: 2})
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := inst.GetJob(5991)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NilError(t, err)
+	}
+	assert.Assert(t, atomic.LoadInt32(&max) <= 2)
+	// 8 requests with a burst of 2 at 200 requests/second require at least 30ms
+	assert.Assert(t, time.Since(start) >= 30*time.Millisecond)
+}
+
+func TestRateLimitStream(t *testing.T) {
+	// Requests within a job iteration must not wait for the slot of the current page
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/jobs", "nested/jobs")
+	server.Handle("/api/v1/jobs/", "jobs/5830/comments")
+	inst := server.Instance()
+	inst.SetRateLimit(RateLimit{MaxInFlight: 1})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	it := inst.IterateJobsContext(ctx, EmptyParams(), 10)
+	defer it.Close()
+	count := 0
+	for it.Next() {
+		_, err := inst.GetCommentsContext(ctx, it.Job().ID)
+		assert.NilError(t, err)
+		count++
+	}
+	assert.NilError(t, it.Err())
+	assert.Equal(t, count, 2)
+	assert.Equal(t, len(server.Requests("/api/v1/jobs/5831/comments")), 1)
+}
+
+func TestJob(t *testing.T) {
+	job, err := instance.GetJob(5991)
+	assert.NilError(t, err)
+	assert.Equal(t, job.Settings.Arch, "x86_64")
+	assert.Equal(t, job.Settings.Machine, "gce_n1_standard_2")
+	assert.Equal(t, job.Settings.Build, "20210419-1")
+	assert.Equal(t, job.Settings.Distri, "sle")
+	assert.Equal(t, job.Settings.Get("FLAVOR"), "GCE-BYOS-Updates")
+	assert.Equal(t, job.Settings.Get("COMMAND_FILE"), "cve")
+	assert.Assert(t, job.Settings.Has("SCC_REGCODE"))
+	// Modified fields are encoded, also if they are cleared
+	job.Settings.Build, job.Settings.Distri = "", "opensuse"
+	buf, err := json.Marshal(job.Settings)
+	assert.NilError(t, err)
+	var settings map[string]string
+	assert.NilError(t, json.Unmarshal(buf, &settings))
+	assert.Equal(t, settings["BUILD"], "")
+	assert.Equal(t, settings["DISTRI"], "opensuse")
+	assert.Equal(t, settings["ARCH"], "x86_64")
+	buf, err = json.Marshal(Settings{Values: map[string]string{"ARCH": "aarch64"}})
+	assert.NilError(t, err)
+	assert.Equal(t, string(buf), `{"ARCH":"aarch64"}`)
+	assert.Equal(t, job.HasParents, 1)
+	assert.DeepEqual(t, job.Assets["hdd"], []string{"publiccloud_12sp4_GCE_BYOS_Updates.qcow2"})
+	assert.Equal(t, job.StartedAt(), time.Date(2021, 4, 19, 12, 40, 57, 0, time.UTC))
+	assert.Equal(t, job.Duration(), 44*time.Minute+14*time.Second)
+	assert.Assert(t, job.CreatedAt().IsZero())
+}
+
+func TestSchedule(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/isos", "schedule/isos")
+	server.Handle("/api/v1/jobs", "schedule/jobs")
+	inst := server.Instance()
+	_, err := inst.PostISO(map[string]string{"DISTRI": "opensuse"})
+	assert.Assert(t, err != nil) // no API key
+	assert.Equal(t, len(server.Requests("")), 0)
+	inst.SetApiKey("key", "secret")
+	result, err := inst.PostISO(map[string]string{"DISTRI": "opensuse", "BUILD": "1+2&3"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result.IDs, []int64{10, 11})
+	assert.Equal(t, result.ScheduledProductID, int64(3))
+	assert.Equal(t, len(result.Failed), 1)
+	assert.Equal(t, result.Failed[0].JobName, "textmode")
+	requests := server.Requests("/api/v1/isos")
+	assert.Equal(t, len(requests), 1)
+	assert.Equal(t, requests[0].Method, "POST")
+	assert.Equal(t, requests[0].Header.Get("X-API-Key"), "key")
+	assert.Assert(t, requests[0].Header.Get("X-API-Hash") != "")
+	assert.Equal(t, requests[0].Form.Get("DISTRI"), "opensuse")
+	assert.Equal(t, requests[0].Form.Get("BUILD"), "1+2&3")
+	ids, err := inst.PostJob(map[string]string{"TEST": "textmode"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ids, []int64{12})
+	requests = server.Requests("/api/v1/jobs")
+	assert.Equal(t, len(requests), 1)
+	assert.Equal(t, requests[0].Form.Get("TEST"), "textmode")
+}
+
+func TestRestart(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/jobs/restart", "actions/restart")
+	inst := server.Instance()
+	result, err := inst.RestartJobs([]int64{5990, 5991}, RestartOptions{SkipParents: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result.Jobs, map[int64]int64{5990: 6000, 5991: 6001})
+	requests := server.Requests("/api/v1/jobs/restart")
+	assert.Equal(t, len(requests), 1)
+	assert.DeepEqual(t, requests[0].Form["jobs"], []string{"5990", "5991"})
+	assert.Equal(t, requests[0].Form.Get("skip_parents"), "1")
+	assert.Equal(t, requests[0].Form.Get("force"), "")
+}
+
+func TestPostComment(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/jobs/5830/comments", "comments/post")
+	inst := server.Instance()
+	id, err := inst.PostComment(COMMENT_TEST_JOB_ID, Comment{Text: "poo#42 & bsc#1337"})
+	assert.NilError(t, err)
+	assert.Equal(t, id, 18)
+	requests := server.Requests("/api/v1/jobs/5830/comments")
+	assert.Equal(t, len(requests), 1)
+	assert.Equal(t, requests[0].Method, "POST")
+	assert.Equal(t, requests[0].Form.Get("text"), "poo#42 & bsc#1337")
+}
+
+func TestTestSuites(t *testing.T) {
+	testsuites, err := instance.GetTestSuites()
+	assert.NilError(t, err)
+	assert.Equal(t, len(testsuites), 3)
+	assert.Equal(t, testsuites[0].ID, 1)
+	assert.Equal(t, testsuites[0].Name, "textmode")
+	assert.Equal(t, testsuites[0].Description, "Installation in text mode")
+	assert.Equal(t, testsuites[0].Settings["DESKTOP"], "textmode")
+	assert.Equal(t, testsuites[0].Settings["VIDEOMODE"], "text")
+	assert.Equal(t, testsuites[1].Name, "kde")
+	assert.Equal(t, testsuites[1].Settings["DESKTOP"], "kde")
+	assert.Equal(t, testsuites[2].Name, "minimalx")
+	assert.Equal(t, len(testsuites[2].Settings), 0)
+}
+
+func TestJobGroups(t *testing.T) {
+	groups, err := instance.GetJobGroups()
+	assert.NilError(t, err)
+	assert.Equal(t, len(groups), 2)
+	assert.Equal(t, groups[0].Name, "openSUSE Tumbleweed")
+	assert.Equal(t, groups[0].DefaultPriority, 50)
+	assert.Equal(t, *groups[0].KeepImportantLogsInDays, FlexInt(120))
+	assert.Equal(t, *groups[0].KeepLogsInDays, FlexInt(30))
+	assert.Equal(t, *groups[0].KeepResultsInDays, FlexInt(365))
+	assert.Equal(t, *groups[0].SizeLimit, FlexInt(100))
+	assert.Equal(t, groups[1].ParentID, 4)
+	assert.Equal(t, groups[1].DefaultPriority, 40)
+	assert.Equal(t, *groups[1].KeepImportantLogsInDays, FlexInt(120))
+	assert.Equal(t, *groups[1].KeepLogsInDays, FlexInt(30))
+	assert.Equal(t, *groups[1].KeepResultsInDays, FlexInt(0))
+	assert.Assert(t, groups[1].SizeLimit == nil)
+	// Retention settings are posted
+	params, err := url.ParseQuery(groups[0].encodeWWW())
+	assert.NilError(t, err)
+	assert.Equal(t, params.Get("keep_logs_in_days"), "30")
+	assert.Equal(t, params.Get("size_limit_gb"), "100")
+	assert.Equal(t, params.Get("default_priority"), "50")
+	// 0 means "keep forever" and is posted as well, unset values are not posted
+	params, err = url.ParseQuery(groups[1].encodeWWW())
+	assert.NilError(t, err)
+	assert.Equal(t, params.Get("keep_results_in_days"), "0")
+	assert.Assert(t, params.Has("keep_results_in_days"))
+	assert.Assert(t, !params.Has("size_limit_gb"))
+	// New groups without retention settings get the defaults of openQA
+	var group JobGroup
+	assert.NilError(t, json.Unmarshal([]byte(`{"name":"new group"}`), &group))
+	params, err = url.ParseQuery(group.encodeWWW())
+	assert.NilError(t, err)
+	for _, key := range []string{"keep_important_logs_in_days", "keep_important_results_in_days", "keep_logs_in_days", "keep_results_in_days", "size_limit_gb"} {
+		assert.Assert(t, !params.Has(key), key)
+	}
+}
+
+func TestIterateJobs(t *testing.T) {
+	// Paged job query with 250 jobs. The first page provides a Link header, the others rely on limit and offset
+	server := newFixtureServer(t)
+	server.HandleFunc("/api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset == 0 {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/jobs?test=textmode&limit=%d&offset=%d>; rel="next"`, r.Host, limit, limit))
+		}
+		jobs := make([]Job, 0)
+		for id := offset + 1; id <= offset+limit && id <= 250; id++ {
+			jobs = append(jobs, Job{ID: int64(id), Test: "textmode"})
+		}
+		buf, _ := json.Marshal(map[string]interface{}{"count": len(jobs), "jobs": jobs})
+		w.Write(buf)
+	})
+	inst := server.Instance()
+	it := inst.IterateJobs(map[string]string{"test": "textmode"}, 100)
+	count := 0
+	for it.Next() {
+		count++
+		assert.Equal(t, it.Job().ID, int64(count))
+	}
+	it.Close()
+	assert.NilError(t, it.Err())
+	assert.Equal(t, count, 250)
+	requests := server.Requests("/api/v1/jobs")
+	assert.Equal(t, len(requests), 3)
+	for _, req := range requests {
+		assert.Equal(t, req.Query.Get("test"), "textmode")
+	}
+	// Stop early
+	it = inst.IterateJobs(map[string]string{"test": "textmode"}, 10)
+	for it.Next() && it.Job().ID < 15 {
+	}
+	it.Close()
+	assert.Assert(t, !it.Next())
+	assert.Equal(t, len(server.Requests("/api/v1/jobs")), 3+2)
+	// Servers that ignore the offset return the same page again. The iteration stops instead of looping forever
+	ignoring := newFixtureServer(t)
+	ignoring.HandleFunc("/api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		jobs := make([]Job, 0)
+		for id := 1; id <= limit; id++ {
+			jobs = append(jobs, Job{ID: int64(id), Test: "textmode"})
+		}
+		buf, _ := json.Marshal(map[string]interface{}{"count": len(jobs), "jobs": jobs})
+		w.Write(buf)
+	})
+	inst = ignoring.Instance()
+	it = inst.IterateJobs(EmptyParams(), 10)
+	count = 0
+	for it.Next() {
+		count++
+	}
+	assert.Assert(t, errors.Is(it.Err(), ErrInvalidResponse))
+	assert.Equal(t, count, 10)
+	assert.Equal(t, len(ignoring.Requests("/api/v1/jobs")), 2)
+	// API keys are not sent to other hosts given in the Link header
+	other := newFixtureServer(t)
+	other.HandleFunc("/api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jobs":[]}`))
+	})
+	linking := newFixtureServer(t)
+	linking.HandleFunc("/api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/jobs?offset=1>; rel="next"`, other.URL))
+		w.Write([]byte(`{"jobs":[{"id":1}]}`))
+	})
+	inst = linking.Instance()
+	inst.SetApiKey("key", "secret")
+	it = inst.IterateJobs(EmptyParams(), 1)
+	for it.Next() {
+	}
+	it.Close()
+	assert.NilError(t, it.Err())
+	assert.Equal(t, linking.Requests("/api/v1/jobs")[0].Header.Get("X-API-Key"), "key")
+	requests = other.Requests("/api/v1/jobs")
+	assert.Equal(t, len(requests), 1)
+	assert.Equal(t, requests[0].Header.Get("X-API-Key"), "")
+	assert.Equal(t, requests[0].Header.Get("X-API-Hash"), "")
+}
+
+func TestJobQuery(t *testing.T) {
+	q := JobQuery{Distri: []string{"opensuse"}, Build: []string{"1+2&3,4"}, Groups: []int{1, 2}, Result: []string{"failed", "incomplete"}, Latest: true, Limit: 10}
+	assert.Equal(t, q.Encode(), "build=1%2B2%263%2C4&distri=opensuse&groupid=1&groupid=2&latest=1&limit=10&result=failed&result=incomplete")
+	// Legacy parameter maps split values on commas only for list parameters
+	q = jobQueryFromParams(map[string]string{"arch": "x86_64", "build": "1,2", "groupid": "3,4", "result": "failed,incomplete", "ids": "1,2", "foo": "bar,baz"})
+	assert.DeepEqual(t, q.Arch, []string{"x86_64"})
+	assert.DeepEqual(t, q.Build, []string{"1,2"})
+	assert.DeepEqual(t, q.Groups, []int{3, 4})
+	assert.DeepEqual(t, q.Result, []string{"failed", "incomplete"})
+	assert.DeepEqual(t, q.Extra["ids"], []string{"1", "2"})
+	assert.DeepEqual(t, q.Extra["foo"], []string{"bar,baz"})
+	// The parameters of the caller are not modified
+	params := EmptyParams()
+	_, err := instance.GetOverview("test", params)
+	assert.NilError(t, err)
+	assert.Equal(t, len(params), 0)
+}
+
+func TestWaitForJob(t *testing.T) {
+	// Job 100 is done and has been cloned as job 101, which finishes after two polls. Job 102 keeps running
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/experimental/jobs/100/status", "wait/status_failed")
+	server.HandleFunc("/api/v1/experimental/jobs/101/status", func(w http.ResponseWriter, r *http.Request) {
+		if len(server.Requests("/api/v1/experimental/jobs/101/status")) < 3 {
+			serveFixture(w, r, "wait/status_running")
+		} else {
+			serveFixture(w, r, "wait/status_passed")
+		}
+	})
+	server.Handle("/api/v1/experimental/jobs/102/status", "wait/status_running")
+	server.Handle("/api/v1/jobs/100", "wait/100")
+	server.Handle("/api/v1/jobs/101", "wait/101")
+	inst := server.Instance()
+	opts := WaitOptions{PollInterval: time.Millisecond, MaxPollInterval: 5 * time.Millisecond}
+	state, err := inst.WaitForJob(100, opts)
+	assert.NilError(t, err)
+	assert.Equal(t, state.ID, int64(100))
+	assert.Equal(t, state.Result, "failed")
+	opts.Follow = true
+	state, err = inst.WaitForJob(100, opts)
+	assert.NilError(t, err)
+	assert.Equal(t, state.ID, int64(101))
+	assert.Equal(t, state.Result, "passed")
+	assert.Equal(t, len(server.Requests("/api/v1/experimental/jobs/101/status")), 3)
+	// Cancelled while waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Millisecond)
+	defer cancel()
+	opts.PollInterval = time.Second
+	_, err = inst.WaitForJobContext(ctx, 102, opts)
+	assert.Assert(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestWaitForJobSubscription(t *testing.T) {
+	// Job 200 finishes after the first poll. The update triggers the second poll before the poll interval elapses
+	server := newFixtureServer(t)
+	server.HandleFunc("/api/v1/experimental/jobs/200/status", func(w http.ResponseWriter, r *http.Request) {
+		if len(server.Requests("/api/v1/experimental/jobs/200/status")) < 2 {
+			serveFixture(w, r, "wait/status_running")
+		} else {
+			serveFixture(w, r, "wait/status_passed")
+		}
+	})
+	inst := server.Instance()
+	messages := make(chan amqp.Delivery, 4)
+	sub := &RabbitMQSubscription{obs: messages}
+	messages <- amqp.Delivery{RoutingKey: "suse.openqa.comment.create", Body: []byte(`{"id":[]}`)} // not a job status
+	messages <- amqp.Delivery{RoutingKey: "suse.openqa.job.done", Body: []byte(`{"id":200,"result":"passed"}`)}
+	start := time.Now()
+	state, err := inst.WaitForJob(200, WaitOptions{PollInterval: 10 * time.Second, Subscription: sub})
+	assert.NilError(t, err)
+	assert.Equal(t, state.Result, "passed")
+	assert.Assert(t, time.Since(start) < 5*time.Second)
+	// The subscription is not consumed anymore once the waiter is done
+	messages <- amqp.Delivery{RoutingKey: "suse.openqa.job.done", Body: []byte(`{"id":201}`)}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	d, err := sub.ReceiveContext(ctx)
+	assert.NilError(t, err)
+	status, err := parseJobStatus(d)
+	assert.NilError(t, err)
+	assert.Equal(t, status.ID, int64(201))
+	// Messages received while the context gets cancelled are kept for the next receive
+	for n := 0; n < 20; n++ {
+		messages <- amqp.Delivery{RoutingKey: "suse.openqa.job.done", Body: []byte(strconv.Itoa(n))}
+		_, err := sub.ReceiveContext(&cancellingContext{Context: context.Background()})
+		assert.Assert(t, errors.Is(err, context.Canceled))
+		d, err := sub.ReceiveContext(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, string(d.Body), strconv.Itoa(n))
+	}
+	// Closed subscriptions end the receiving
+	close(messages)
+	_, err = sub.ReceiveContext(ctx)
+	assert.ErrorContains(t, err, "EOF")
+}
+
+/* Context that is done, but reports it only after the first check of its error
+ * This simulates a context that gets cancelled while a receive is in progress
+ */
+type cancellingContext struct {
+	context.Context
+	checked bool
+}
+
+func (c *cancellingContext) Done() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+func (c *cancellingContext) Err() error {
+	if !c.checked {
+		c.checked = true
+		return nil
+	}
+	return context.Canceled
+}
+
+func TestJobGraph(t *testing.T) {
+	// Job 5991 is chained to 5990
+	graph, err := instance.GetJobGraph(5991, false)
+	assert.NilError(t, err)
+	assert.Equal(t, graph.Root, int64(5991))
+	assert.DeepEqual(t, graph.IDs(), []int64{5990, 5991})
+	assert.DeepEqual(t, graph.Edges, []JobDependency{{Parent: 5990, Child: 5991, Type: DependencyChained}})
+	assert.DeepEqual(t, graph.Ancestors(5991), []int64{5990})
+	assert.DeepEqual(t, graph.Descendants(5990), []int64{5991})
+	assert.DeepEqual(t, graph.Roots(), []int64{5990})
+	assert.DeepEqual(t, graph.Blockers(5991), []int64{})
+	order, err := graph.TopologicalOrder()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, order, []int64{5990, 5991})
+
+	// 1 -> 2 (parallel 3), 2 -> 4 (chained). The parallel job 3 does not block 2
+	jobs := []Job{
+		{ID: 4, State: "blocked", Parents: Children{Chained: []int64{2}}},
+		{ID: 1, State: "running", Children: Children{Chained: []int64{2}}},
+		{ID: 2, State: "scheduled", Parents: Children{Chained: []int64{1}, Parallel: []int64{3}}},
+		{ID: 3, State: "scheduled", Children: Children{Parallel: []int64{2}}},
+	}
+	graph = NewJobGraph(jobs)
+	assert.Equal(t, len(graph.Edges), 3)
+	assert.DeepEqual(t, graph.Blockers(4), []int64{1, 2})
+	assert.DeepEqual(t, graph.Blockers(2), []int64{1})
+	assert.DeepEqual(t, graph.Parallel(2), []int64{3})
+	order, err = graph.TopologicalOrder()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, order, []int64{1, 3, 2, 4})
+	// Cycles are detected
+	jobs[1].Parents = Children{Chained: []int64{4}}
+	graph = NewJobGraph(jobs)
+	_, err = graph.TopologicalOrder()
+	assert.Assert(t, errors.Is(err, ErrDependencyCycle))
+}
+
+func TestJobGraphExport(t *testing.T) {
+	jobs := []Job{
+		{ID: 1, Test: "server", State: "done", Result: "failed", Children: Children{Parallel: []int64{2}}},
+		{ID: 2, Test: "client", State: "done", Result: "parallel_failed", Parents: Children{Parallel: []int64{1}}},
+		{ID: 3, Test: "cleanup", State: "scheduled", Parents: Children{Chained: []int64{2}}},
+	}
+	graph := NewJobGraph(jobs)
+	dot := graph.DOT()
+	assert.Assert(t, strings.HasPrefix(dot, "digraph \"job_1\" {"))
+	assert.Assert(t, strings.Contains(dot, "\t1 [label=\"1 server\\nfailed\", fillcolor=\"#d9534f\", penwidth=3];"), dot)
+	assert.Assert(t, strings.Contains(dot, "\t1 -> 2 [label=\"Parallel\", style=dashed, dir=none];"), dot)
+	assert.Assert(t, strings.Contains(dot, "\t2 -> 3 [label=\"Chained\"];"), dot)
+	mermaid := graph.Mermaid()
+	assert.Assert(t, strings.HasPrefix(mermaid, "flowchart TD\n"))
+	assert.Assert(t, strings.Contains(mermaid, "\tjob3[\"3 cleanup<br>scheduled\"]\n"), mermaid)
+	assert.Assert(t, strings.Contains(mermaid, "\tjob1 -.-|Parallel| job2\n"), mermaid)
+	assert.Assert(t, strings.Contains(mermaid, "\tstyle job1 fill:#d9534f\n"), mermaid)
+	// Only quotes and backslashes are escaped in DOT, other characters are passed as they are
+	graph = NewJobGraph([]Job{{ID: 4, Test: `café "quoted" \`, State: "scheduled"}})
+	dot = graph.DOT()
+	assert.Assert(t, strings.Contains(dot, `4 [label="4 café \"quoted\" \\\nscheduled"`), dot)
+}
+
+func TestJobFiles(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/jobs/5990/details", "files/details")
+	server.Handle("/api/v1/jobs/5992/details", "files/details_subdir")
+	server.Handle("/api/v1/jobs/5993/details", "files/details_duplicate")
+	server.HandleFunc("/tests/", func(w http.ResponseWriter, r *http.Request) {
+		// Path: /tests/<id>/file/<name>
+		parts := strings.SplitN(r.URL.Path, "/", 5)
+		serveFixture(w, r, filepath.Join("files", parts[4]))
+	})
+	inst := server.Instance()
+	results, err := inst.GetJobResultFiles(5990)
+	assert.NilError(t, err)
+	assert.Equal(t, len(results), 3)
+	assert.Equal(t, results[0].URL, server.URL+"/tests/5990/file/autoinst-log.txt")
+	assert.Assert(t, !results[1].Uploaded)
+	assert.Assert(t, results[2].Uploaded)
+
+	r, err := inst.DownloadJobFile(5990, "autoinst-log.txt")
+	assert.NilError(t, err)
+	buf, err := io.ReadAll(r)
+	r.Close()
+	assert.NilError(t, err)
+	assert.Equal(t, string(buf), "autoinst log\n")
+	_, err = inst.DownloadJobFile(5990, "serial0.txt")
+	assert.Assert(t, errors.Is(err, ErrNotFound))
+
+	dir := t.TempDir()
+	paths, err := inst.MirrorJobFiles(5990, dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(paths), 3)
+	for _, name := range []string{"autoinst-log.txt", "vars.json", "y2logs.tar.bz2"} {
+		buf, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NilError(t, err)
+		expected, err := os.ReadFile(filepath.Join("test", "files", name))
+		assert.NilError(t, err)
+		assert.Equal(t, string(buf), string(expected))
+	}
+	// Subdirectories are kept, so files with the same name don't overwrite each other
+	dir = t.TempDir()
+	paths, err = inst.MirrorJobFiles(5992, dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, paths, []string{filepath.Join(dir, "x.log"), filepath.Join(dir, "ulogs", "x.log")})
+	buf, err = os.ReadFile(filepath.Join(dir, "ulogs", "x.log"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(buf), "uploaded x\n")
+	// Files that would be written to the same path are rejected before anything is downloaded
+	dir = t.TempDir()
+	_, err = inst.MirrorJobFiles(5993, dir)
+	assert.ErrorContains(t, err, "duplicate file name")
+	entries, err := os.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 0)
+}
+
+func TestJobDetails(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/jobs/5990/details", "details/5990")
+	inst := server.Instance()
+	job, err := inst.GetJobDetails(5990)
+	assert.NilError(t, err)
+	assert.Equal(t, len(job.TestResults), 3)
+	assert.Assert(t, job.TestResults[0].HasFlag("fatal"))
+	assert.Assert(t, !job.TestResults[0].HasFlag("milestone"))
+	assert.Assert(t, job.TestResults[1].HasFlag("important"))
+	assert.Equal(t, job.TestResults[1].Details[0].TextData, "firefox started")
+	module, ok := job.FirstFailedModule()
+	assert.Assert(t, ok)
+	assert.Equal(t, module.Name, "firefox")
+	assert.Equal(t, len(job.FailedSteps()), 2)
+	needles := job.FailedNeedles()
+	assert.Equal(t, len(needles), 1)
+	assert.Equal(t, needles[0].Module, "firefox")
+	assert.Equal(t, needles[0].Step.Num, 2)
+	assert.DeepEqual(t, needles[0].Step.Needles, []NeedleMatch{{Name: "firefox-url-20230101", Error: 0.31}})
+	// Without details, the module list is used
+	job = Job{Modules: []Module{{Name: "boot", Result: "passed"}, {Name: "kernel", Result: "failed", Flags: []string{"fatal"}}}}
+	module, ok = job.FirstFailedModule()
+	assert.Assert(t, ok)
+	assert.Equal(t, module.Name, "kernel")
+	assert.Assert(t, module.HasFlag("fatal"))
+	job.Modules = job.Modules[:1]
+	_, ok = job.FirstFailedModule()
+	assert.Assert(t, !ok)
+}
+
+func TestWorkerManagement(t *testing.T) {
+	workers, err := instance.GetWorkers()
+	assert.NilError(t, err)
+	assert.Equal(t, len(workers), 2)
+	assert.DeepEqual(t, workers[0].Classes(), []string{"qemu_x86_64", "qemu_i686", "qemu_i586"})
+	assert.Equal(t, workers[0].JobToken(), "1")
+	assert.Equal(t, workers[1].Name(), "d465:1")
+	assert.Equal(t, len(FilterWorkers(workers, WorkerByClass("qemu_i686"), WorkerByStatus("dead"))), 2)
+	assert.Equal(t, len(FilterWorkers(workers, WorkerByHost("d465"))), 1)
+	assert.Equal(t, len(FilterWorkers(workers, WorkerByAlive(true))), 0)
+
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/workers", "offline_workers/workers")
+	server.HandleFunc("/api/v1/workers/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			serveFixture(w, r, "offline_workers/deleted")
+		} else {
+			serveFixture(w, r, "offline_workers/"+strings.TrimPrefix(r.URL.Path, "/api/v1/workers/"))
+		}
+	})
+	inst := server.Instance()
+	worker, err := inst.GetWorker(2)
+	assert.NilError(t, err)
+	assert.Equal(t, worker.JobID, int64(5990))
+	assert.Equal(t, worker.JobToken(), "abc")
+	assert.DeepEqual(t, worker.Classes(), []string{"qemu_x86_64", "tap"})
+	_, err = inst.GetWorker(4)
+	assert.Assert(t, errors.Is(err, ErrNotFound))
+	// Deleting requires an API key
+	_, err = inst.DeleteOfflineWorkers()
+	assert.ErrorContains(t, err, "API key")
+	inst.SetApiKey("key", "secret")
+	workers, err = inst.DeleteOfflineWorkers(WorkerByHost("worker1"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(workers), 1)
+	deleted := make([]string, 0)
+	for _, req := range server.Requests("") {
+		if req.Method == "DELETE" {
+			deleted = append(deleted, req.Path)
+		}
+	}
+	assert.DeepEqual(t, deleted, []string{"/api/v1/workers/1"})
+}
+
+func TestAssets(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/assets", "asset_status/assets")
+	server.HandleFunc("/api/v1/assets/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/assets/")
+		if r.Method == "DELETE" {
+			serveFixture(w, r, "asset_status/deleted")
+		} else if path == "1" || path == "iso/openSUSE-Tumbleweed.iso" {
+			serveFixture(w, r, "asset_status/asset")
+		} else {
+			http.NotFound(w, r)
+		}
+	})
+	server.Handle("/admin/assets/status", "asset_status/status")
+	inst := server.Instance()
+	assets, err := inst.GetAssets()
+	assert.NilError(t, err)
+	assert.Equal(t, len(assets), 2)
+	assert.Equal(t, assets[0].Size, FlexInt64(4300000000))
+	assert.Assert(t, bool(assets[1].Fixed))
+	asset, err := inst.GetAsset(1)
+	assert.NilError(t, err)
+	assert.Equal(t, asset.Name, "openSUSE-Tumbleweed.iso")
+	asset, err = inst.GetAssetByName("iso", "openSUSE-Tumbleweed.iso")
+	assert.NilError(t, err)
+	assert.Equal(t, asset.ID, 1)
+	_, err = inst.GetAsset(3)
+	assert.Assert(t, errors.Is(err, ErrNotFound))
+	// Responses without assets are invalid
+	invalid := newFixtureServer(t)
+	invalid.Handle("/api/v1/assets", "asset_status/empty")
+	invalidInst := invalid.Instance()
+	_, err = invalidInst.GetAssets()
+	assert.Assert(t, errors.Is(err, ErrInvalidResponse))
+
+	status, err := inst.GetAssetsStatus()
+	assert.NilError(t, err)
+	assert.Equal(t, len(status.Assets), 2)
+	assert.Equal(t, status.Assets[0].Groups[2], int64(5991))
+	assert.Equal(t, status.Groups[1].Name, "openSUSE Tumbleweed")
+	assert.Equal(t, status.Groups[1].Size, FlexInt64(4300000050))
+	assert.Equal(t, status.Groups[2].Picked, FlexInt64(4300000000))
+	assert.DeepEqual(t, AssetSizePerGroup(status.Assets), map[int]int64{1: 4300000050, 2: 4300000000})
+
+	assert.ErrorContains(t, inst.DeleteAsset(1), "API key")
+	inst.SetApiKey("key", "secret")
+	assert.NilError(t, inst.DeleteAsset(1))
+	assert.NilError(t, inst.DeleteAssetByName("hdd", "sle 15.qcow2"))
+	deleted := make([]string, 0)
+	for _, req := range server.Requests("") {
+		if req.Method == "DELETE" {
+			deleted = append(deleted, req.Path)
+		}
+	}
+	assert.DeepEqual(t, deleted, []string{"/api/v1/assets/1", "/api/v1/assets/hdd/sle 15.qcow2"})
+}
+
+func TestBuildResults(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/group_overview/1.json", "builds/group_overview_1.json")
+	server.Handle("/group_overview/3.json", "builds/group_overview_3.json")
+	server.Handle("/parent_group_overview/2.json", "builds/parent_group_overview_2.json")
+	inst := server.Instance()
+	// Sort by time
+	builds, err := inst.GetJobGroupBuildResults(JobGroup{ID: 1}, 3)
+	assert.NilError(t, err)
+	assert.Equal(t, len(builds), 3)
+	assert.Equal(t, builds[0].Build, "9.1")
+	assert.Equal(t, builds[1].Build, "20230109")
+	assert.Equal(t, int(builds[1].Passed), 7)
+	assert.Assert(t, bool(builds[1].Reviewed))
+	assert.Assert(t, !builds[2].IsFinished())
+	// Sort by version
+	builds, err = inst.GetJobGroupBuildResults(JobGroup{ID: 1, BuildVersionSort: 1}, 3)
+	assert.NilError(t, err)
+	assert.Equal(t, builds[0].Build, "20230110")
+	assert.Equal(t, builds[2].Build, "9.1")
+	requests := server.Requests("/group_overview/1.json")
+	assert.Equal(t, len(requests), 2)
+	for _, req := range requests {
+		assert.Equal(t, req.Query.Get("limit_builds"), "3")
+	}
+	// Builds are ordered by version first
+	builds, err = inst.GetJobGroupBuildResults(JobGroup{ID: 3, BuildVersionSort: 1}, 0)
+	assert.NilError(t, err)
+	keys := make([]string, 0)
+	for _, build := range builds {
+		keys = append(keys, build.Key)
+	}
+	assert.DeepEqual(t, keys, []string{"15-SP5-110.1", "15-SP5-100.1", "15-SP4-200.1", "15-SP4-150.1"})
+	// Parent group
+	builds, err = inst.GetParentJobGroupBuildResults(JobGroup{ID: 2}, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(builds), 1)
+	assert.Equal(t, int(builds[0].Children[3].Passed), 1)
+
+	assert.Equal(t, compareVersions("1.10", "1.9"), 1)
+	assert.Equal(t, compareVersions("Build1.2", "Build1.2"), 0)
+	assert.Equal(t, compareVersions("15-SP4", "15-SP5"), -1)
+	assert.Equal(t, compareVersions("1.0", "1.0.1"), -1)
+}
+
+func TestScheduledProducts(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/isos/7", "scheduled_products/7")
+	server.Handle("/api/v1/jobs", "scheduled_products/jobs")
+	server.Handle("/api/v1/isos/7/cancel", "scheduled_products/cancel")
+	inst := server.Instance()
+	product, err := inst.GetScheduledProduct(7)
+	assert.NilError(t, err)
+	assert.Equal(t, product.Status, ScheduledProductScheduled)
+	assert.Assert(t, product.IsFinished())
+	assert.DeepEqual(t, product.JobIDs, []int64{5990, 5991})
+	assert.Equal(t, product.Results.FailedJobInfo[0].JobName, "kde")
+	assert.Equal(t, server.Requests("/api/v1/isos/7")[0].Query.Get("include_job_ids"), "1")
+	jobs, err := inst.GetScheduledProductFailedJobs(7)
+	assert.NilError(t, err)
+	assert.Equal(t, len(jobs), 1)
+	assert.Equal(t, jobs[0].ID, int64(5990))
+	assert.DeepEqual(t, server.Requests("/api/v1/jobs")[0].Query["ids"], []string{"5990", "5991"})
+	assert.NilError(t, inst.CancelScheduledProduct(7))
+	cancelled := server.Requests("/api/v1/isos/7/cancel")
+	assert.Equal(t, len(cancelled), 1)
+	assert.Equal(t, cancelled[0].Method, "POST")
+}
+
+func TestBugs(t *testing.T) {
+	server := newFixtureServer(t)
+	server.HandleFunc("/api/v1/bugs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("refreshable") == "1" {
+			serveFixture(w, r, "bugs/refreshable")
+		} else {
+			serveFixture(w, r, "bugs/bugs")
+		}
+	})
+	server.Handle("/api/v1/bugs/1", "bugs/1")
+	server.Handle("/api/v1/bugs/2", "bugs/2")
+	inst := server.Instance()
+	bugs, err := inst.GetBugs()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, bugs, []Bug{{ID: 1, BugID: "bsc#1234"}, {ID: 2, BugID: "poo#42"}})
+	bugs, err = inst.GetRefreshableBugs(2 * time.Hour)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, bugs, []Bug{{ID: 2, BugID: "poo#42"}})
+	requests := server.Requests("/api/v1/bugs")
+	assert.Equal(t, len(requests), 2)
+	assert.Equal(t, requests[1].Query.Get("delta"), "7200")
+	// openQA ignores a delta of 0, so at least one second is sent
+	_, err = inst.GetRefreshableBugs(0)
+	assert.NilError(t, err)
+	requests = server.Requests("/api/v1/bugs")
+	assert.Equal(t, len(requests), 3)
+	assert.Equal(t, requests[2].Query.Get("refreshable"), "1")
+	assert.Equal(t, requests[2].Query.Get("delta"), "1")
+	bug, err := inst.GetBug(1)
+	assert.NilError(t, err)
+	assert.Equal(t, bug.Resolution, "FIXED")
+	assert.Assert(t, bug.IsClosed())
+	assert.Equal(t, bug.UpdatedAt().Year(), 2023)
+
+	comment := Comment{Text: "bsc#1234 poo#42 boo#1", BugRefs: []string{"bsc#1234", "poo#42", "boo#1"}}
+	resolved, err := inst.GetCommentBugs(comment)
+	assert.NilError(t, err)
+	assert.Equal(t, len(resolved), 2)
+	assert.Equal(t, resolved[0].BugID, "bsc#1234")
+	// Bugs that have not been refreshed are not considered closed
+	assert.Assert(t, !resolved[1].IsClosed())
+	// The references of many comments are resolved with a single list request
+	before := len(server.Requests(""))
+	refs, err := inst.ResolveBugRefs([]string{"poo#42", "bsc#1234", "poo#42", "boo#1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(refs), 2)
+	assert.Equal(t, refs["poo#42"].ID, 2)
+	assert.Equal(t, len(server.Requests(""))-before, 3)
+}
+
+func TestAuditEvents(t *testing.T) {
+	server := newFixtureServer(t)
+	server.HandleFunc("/admin/auditlog/ajax", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == "0" {
+			serveFixture(w, r, "auditlog/page1")
+		} else {
+			serveFixture(w, r, "auditlog/page2")
+		}
+	})
+	inst := server.Instance()
+	inst.SetSessionCookie(&http.Cookie{Name: "mojolicious", Value: "session"})
+	q := AuditQuery{User: "admin", Event: "table_update", NewerRelative: "yesterday", Limit: 2}
+	page, err := inst.GetAuditEvents(q)
+	assert.NilError(t, err)
+	assert.Equal(t, page.Total, 3)
+	assert.Equal(t, len(page.Events), 2)
+	assert.Equal(t, page.Events[0].ID, int64(27))
+	data, err := page.Events[0].Data()
+	assert.NilError(t, err)
+	assert.Equal(t, data["table"], "Machines")
+	assert.Equal(t, page.Events[0].Time(), time.Date(2023, 1, 9, 10, 12, 41, 0, time.UTC))
+	requests := server.Requests("/admin/auditlog/ajax")
+	assert.Equal(t, len(requests), 1)
+	assert.Equal(t, requests[0].Query.Get("search[value]"), "user:admin event:table_update newer:yesterday")
+	// Times are passed in RFC 3339
+	newer := AuditQuery{Newer: time.Date(2023, 1, 9, 11, 0, 0, 0, time.FixedZone("CET", 3600)), OlderRelative: "1 hour ago"}
+	assert.Equal(t, newer.search(), "newer:2023-01-09T10:00:00Z older:1 hour ago")
+	assert.Equal(t, requests[0].Query.Get("length"), "2")
+	cookie, err := (&http.Request{Header: requests[0].Header}).Cookie("mojolicious")
+	assert.NilError(t, err)
+	assert.Equal(t, cookie.Value, "session")
+
+	all, err := inst.GetAllAuditEvents(q)
+	assert.NilError(t, err)
+	assert.Equal(t, len(all), 3)
+	assert.Equal(t, all[2].ID, int64(19))
+	requests = server.Requests("/admin/auditlog/ajax")
+	assert.Equal(t, len(requests), 3)
+	assert.Equal(t, requests[1].Query.Get("start"), "0")
+	assert.Equal(t, requests[2].Query.Get("start"), "2")
+}
+
+func TestEffectiveSettings(t *testing.T) {
+	server := newFixtureServer(t)
+	server.Handle("/api/v1/products/1", "effective/product")
+	server.Handle("/api/v1/machines/1", "effective/machine")
+	server.Handle("/api/v1/test_suites/1", "effective/test_suite")
+	server.Handle("/api/v1/job_templates/1", "effective/job_template")
+	inst := server.Instance()
+	template, err := inst.GetJobTemplate(1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, template.Settings, map[string]string{"DESKTOP": "minimalx", "+WORKER_CLASS": "qemu_x86_64,svirt"})
+	// Settings survive encoding
+	buf, err := json.Marshal(template)
+	assert.NilError(t, err)
+	var decoded JobTemplate
+	assert.NilError(t, json.Unmarshal(buf, &decoded))
+	assert.DeepEqual(t, decoded, template)
+	settings, err := inst.GetEffectiveSettings(template, map[string]string{"BUILD": "20230109", "desktop": "kde", "+HDD_1": "custom.qcow2", "QEMURAM": "1024", "VIDEOMODE": "gfx"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, settings.Values(), map[string]string{
+		"ARCH": "x86_64", "BACKEND": "qemu", "BUILD": "20230109", "DESKTOP": "kde", "DISTRI": "opensuse", "FLAVOR": "DVD", "HDD_1": "custom.qcow2",
+		"MACHINE": "64bit", "QEMURAM": "1024", "TEST": "textmode", "VERSION": "Tumbleweed", "VIDEOMODE": "text", "WORKER_CLASS": "qemu_x86_64,svirt,tap",
+	})
+	assert.Equal(t, settings["BUILD"].Source, SettingFromOverride)
+	// Scheduling parameters override all stored settings
+	assert.Equal(t, settings["QEMURAM"].Source, SettingFromOverride)
+	assert.DeepEqual(t, settings["QEMURAM"].Shadowed, []SettingValue{{Value: "2048", Source: SettingFromProduct}, {Value: "4096", Source: SettingFromMachine}})
+	assert.Equal(t, settings["DESKTOP"].Source, SettingFromOverride)
+	assert.DeepEqual(t, settings["DESKTOP"].Shadowed, []SettingValue{{Value: "textmode", Source: SettingFromTestSuite}, {Value: "minimalx", Source: SettingFromJobTemplate}})
+	// Settings with + prefix override everything, also if they are stored
+	assert.Equal(t, settings["HDD_1"].Source, SettingFromForcedOverride)
+	assert.Equal(t, len(settings["HDD_1"].Shadowed), 2)
+	assert.Equal(t, settings["VIDEOMODE"].Source, SettingFromTestSuite)
+	assert.DeepEqual(t, settings["VIDEOMODE"].Shadowed, []SettingValue{{Value: "gfx", Source: SettingFromOverride}})
+	_, ok := settings["+VIDEOMODE"]
+	assert.Assert(t, !ok)
+	// Worker classes with + prefix are merged as well
+	assert.Equal(t, settings["WORKER_CLASS"].Source, SettingFromWorkerClasses)
+	_, ok = settings["+WORKER_CLASS"]
+	assert.Assert(t, !ok)
+	assert.Equal(t, settings.Keys()[0], "ARCH")
+	// Also a single worker class is merged
+	single := JobTemplate{Machine: Machine{Settings: map[string]string{"WORKER_CLASS": "qemu_x86_64"}}, Settings: map[string]string{"+WORKER_CLASS": "qemu_x86_64"}}
+	settings = single.EffectiveSettings(nil)
+	assert.Equal(t, settings["WORKER_CLASS"].Value, "qemu_x86_64")
+	assert.Equal(t, settings["WORKER_CLASS"].Source, SettingFromWorkerClasses)
+}
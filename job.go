@@ -1,26 +1,39 @@
 package gopenqa
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 /* Job instance */
 type Job struct {
-	AssignedWorkerID int      `json:"assigned_worker_id"`
-	BlockedByID      int      `json:"blocked_by_id"`
-	Children         Children `json:"children"`
-	Parents          Children `json:"parents"`
-	CloneID          int64    `json:"clone_id"`
-	GroupID          int      `json:"group_id"`
-	ID               int64    `json:"id"`
-	// Modules
-	Name string `json:"name"`
-	// Parents
-	Priority  int      `json:"priority"`
-	Result    string   `json:"result"`
-	Settings  Settings `json:"settings"`
-	State     string   `json:"state"`
-	Tfinished string   `json:"t_finished"`
-	Tstarted  string   `json:"t_started"`
-	Test      string   `json:"test"`
+	Assets           map[string][]string `json:"assets"` // Assets by type, e.g. "hdd" or "iso"
+	AssignedWorkerID int                 `json:"assigned_worker_id"`
+	BlockedByID      int                 `json:"blocked_by_id"`
+	Children         Children            `json:"children"`
+	Parents          Children            `json:"parents"`
+	CloneID          int64               `json:"clone_id"`
+	OriginID         int64               `json:"origin_id"` // ID of the job this job has been cloned from
+	Group            string              `json:"group"`     // Name of the job group
+	GroupID          int                 `json:"group_id"`
+	HasParents       int                 `json:"has_parents"`
+	ParentsOK        int                 `json:"parents_ok"`
+	ID               int64               `json:"id"`
+	Logs             []string            `json:"logs"`  // Result files, only present in the job details
+	ULogs            []string            `json:"ulogs"` // Files uploaded by the test, only present in the job details
+	Modules          []Module            `json:"modules"`
+	TestResults      []ModuleResult      `json:"testresults"` // Module results including steps, only present in the job details
+	Name             string              `json:"name"`
+	Priority         int                 `json:"priority"`
+	Reason           string              `json:"reason"` // Reason for the result, e.g. for incompletes
+	Result           string              `json:"result"`
+	Settings         Settings            `json:"settings"`
+	State            string              `json:"state"`
+	Tcreated         string              `json:"t_created"`
+	Tfinished        string              `json:"t_finished"`
+	Tstarted         string              `json:"t_started"`
+	Test             string              `json:"test"`
 	/* this is added by the program and not part of the fetched json */
 	Link     string
 	Prefix   string
@@ -35,20 +48,103 @@ type Children struct {
 	Parallel        []int64 `json:"Parallel"`
 }
 
-/* Job Setting struct */
+/* Job Setting struct
+ * The most common settings are available as fields, all settings are in the Values map
+ * When encoding, modified fields take precedence over Values. Setting a field to an empty string clears the setting
+ */
 type Settings struct {
-	Arch    string `json:"ARCH"`
-	Backend string `json:"BACKEND"`
-	Machine string `json:"MACHINE"`
+	Arch    string            `json:"ARCH"`
+	Backend string            `json:"BACKEND"`
+	Build   string            `json:"BUILD"`
+	Distri  string            `json:"DISTRI"`
+	Flavor  string            `json:"FLAVOR"`
+	Machine string            `json:"MACHINE"`
+	Version string            `json:"VERSION"`
+	Values  map[string]string `json:"-"` // All job settings
+	decoded map[string]string // Fields as they have been decoded, to detect modifications
+}
+
+/* Test module of a job */
+type Module struct {
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Result   string   `json:"result"`
+	Flags    []string `json:"flags"` // e.g. "fatal", "important", "milestone"
+}
+
+// Get returns the value of the given setting or an empty string, if not present
+func (s *Settings) Get(key string) string {
+	return s.Values[key]
+}
+
+// Has returns true, if the given setting is present
+func (s *Settings) Has(key string) bool {
+	_, ok := s.Values[key]
+	return ok
+}
+
+func (s *Settings) UnmarshalJSON(data []byte) error {
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	s.Values = make(map[string]string, len(values))
+	for k, v := range values {
+		switch value := v.(type) {
+		case nil:
+			s.Values[k] = ""
+		case string:
+			s.Values[k] = value
+		default:
+			// Numbers and everything else as it came in the JSON
+			if buf, err := json.Marshal(value); err == nil {
+				s.Values[k] = string(buf)
+			}
+		}
+	}
+	s.Arch = s.Values["ARCH"]
+	s.Backend = s.Values["BACKEND"]
+	s.Build = s.Values["BUILD"]
+	s.Distri = s.Values["DISTRI"]
+	s.Flavor = s.Values["FLAVOR"]
+	s.Machine = s.Values["MACHINE"]
+	s.Version = s.Values["VERSION"]
+	s.decoded = s.fields()
+	return nil
+}
+
+// fields returns the values of the typed fields by their setting key
+func (s *Settings) fields() map[string]string {
+	return map[string]string{"ARCH": s.Arch, "BACKEND": s.Backend, "BUILD": s.Build, "DISTRI": s.Distri, "FLAVOR": s.Flavor, "MACHINE": s.Machine, "VERSION": s.Version}
+}
+
+func (s Settings) MarshalJSON() ([]byte, error) {
+	values := make(map[string]string, len(s.Values))
+	for k, v := range s.Values {
+		values[k] = v
+	}
+	// The typed fields take precedence, if they have been modified. Unmodified empty fields don't clear Values
+	for key, value := range s.fields() {
+		if value != s.decoded[key] {
+			values[key] = value
+		}
+	}
+	return json.Marshal(values)
 }
 
 /* Special struct for getting quick job status */
 type JobState struct {
+	ID        int64  `json:"id"`
 	BlockedBy int64  `json:"blocked_by_id"`
 	Result    string `json:"result"`
 	State     string `json:"state"`
 }
 
+/* IsFinal returns true, if the job will not change its state anymore */
+func (s *JobState) IsFinal() bool {
+	return s.State == "done" || s.State == "cancelled"
+}
+
 /* Format job as a string */
 func (j *Job) String() string {
 	return fmt.Sprintf("%d %s (%s)", j.ID, j.Name, j.Test)
@@ -62,11 +158,70 @@ func (j *Job) JobState() string {
 	return j.State
 }
 
+/* CreatedAt returns the time the job has been created or the zero time, if not present */
+func (j *Job) CreatedAt() time.Time {
+	return parseTimestamp(j.Tcreated)
+}
+
+/* StartedAt returns the time the job has been started or the zero time, if not started */
+func (j *Job) StartedAt() time.Time {
+	return parseTimestamp(j.Tstarted)
+}
+
+/* FinishedAt returns the time the job has been finished or the zero time, if not finished */
+func (j *Job) FinishedAt() time.Time {
+	return parseTimestamp(j.Tfinished)
+}
+
+/* Duration returns the run time of a finished job or 0, if the job has not finished yet */
+func (j *Job) Duration() time.Duration {
+	started, finished := j.StartedAt(), j.FinishedAt()
+	if started.IsZero() || finished.IsZero() {
+		return 0
+	}
+	return finished.Sub(started)
+}
+
+/* HasFlag returns true, if the module has the given flag set */
+func (m *Module) HasFlag(flag string) bool {
+	for _, f := range m.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+/* parseTimestamp parses a openQA timestamp. openQA timestamps without timezone are in UTC
+ * Returns the zero time for empty or invalid timestamps
+ */
+func parseTimestamp(timestamp string) time.Time {
+	layouts := []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05 -0700", "2006-01-02 15:04:05", time.RFC3339}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, timestamp); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 /* IsCloned returns true, if the job has been cloned/restarted */
 func (j *Job) IsCloned() bool {
 	return j.CloneID != 0 && j.CloneID != j.ID
 }
 
+/* IsFailed returns true, if the job has finished with a failed result, i.e. failed, incomplete, timeout_exceeded or parallel_failed */
+func (j *Job) IsFailed() bool {
+	if j.State != "done" {
+		return false
+	}
+	switch j.Result {
+	case "failed", "incomplete", "timeout_exceeded", "parallel_failed":
+		return true
+	}
+	return false
+}
+
 /* Compares two jobs according to their unique parameters (ID, GroupID, Test) */
 func (j1 *Job) Equals(j2 Job) bool {
 	// Compare only relevant parameters
@@ -0,0 +1,228 @@
+package gopenqa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/* Options for restarting jobs */
+type RestartOptions struct {
+	Force                bool // Restart even if openQA refuses it, e.g. because of missing assets
+	SkipParents          bool // Don't restart the parent jobs
+	SkipChildren         bool // Don't restart the child jobs
+	SkipOkResultChildren bool // Don't restart child jobs that passed or softfailed
+}
+
+/* Result of restarting jobs */
+type RestartResult struct {
+	Jobs        map[int64]int64 // Mapping from the original job IDs to the IDs of the new jobs
+	Errors      []string        // Reasons why jobs could not be restarted
+	Warnings    []string
+	Enforceable bool // True if the restart can be forced despite errors
+}
+
+func (opts *RestartOptions) encodeWWW(params url.Values) {
+	if opts.Force {
+		params.Add("force", "1")
+	}
+	if opts.SkipParents {
+		params.Add("skip_parents", "1")
+	}
+	if opts.SkipChildren {
+		params.Add("skip_children", "1")
+	}
+	if opts.SkipOkResultChildren {
+		params.Add("skip_ok_result_children", "1")
+	}
+}
+
+// parseJobMapping merges the list of {"old id": new id} dicts openQA returns for restarted and duplicated jobs
+func parseJobMapping(mappings []map[string]int64) (map[int64]int64, error) {
+	ret := make(map[int64]int64, 0)
+	for _, mapping := range mappings {
+		for old, id := range mapping {
+			oldID, err := strconv.ParseInt(old, 10, 64)
+			if err != nil {
+				return ret, ErrInvalidResponse
+			}
+			ret[oldID] = id
+		}
+	}
+	return ret, nil
+}
+
+func (i *Instance) restartJobs(ctx context.Context, rurl string, params url.Values, opts RestartOptions) (RestartResult, error) {
+	result := RestartResult{Jobs: make(map[int64]int64, 0)}
+	opts.encodeWWW(params)
+	buf, err := i.post(ctx, rurl, []byte(params.Encode()))
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	if err != nil {
+		return result, err
+	}
+	var obj struct {
+		Result      []map[string]int64 `json:"result"`
+		Errors      []string           `json:"errors"`
+		Warnings    []string           `json:"warnings"`
+		Enforceable int                `json:"enforceable"`
+	}
+	if err := json.Unmarshal(buf, &obj); err != nil {
+		return result, err
+	}
+	result.Errors = obj.Errors
+	result.Warnings = obj.Warnings
+	result.Enforceable = obj.Enforceable != 0
+	if result.Jobs, err = parseJobMapping(obj.Result); err != nil {
+		return result, err
+	}
+	if len(result.Errors) > 0 {
+		return result, fmt.Errorf("restart failed: %s", strings.Join(result.Errors, "; "))
+	}
+	return result, nil
+}
+
+/* Restart the given job. Returns the mapping of the original jobs to the restarted ones */
+func (i *Instance) RestartJob(id int64, opts RestartOptions) (RestartResult, error) {
+	return i.RestartJobContext(context.Background(), id, opts)
+}
+
+func (i *Instance) RestartJobContext(ctx context.Context, id int64, opts RestartOptions) (RestartResult, error) {
+	rurl := fmt.Sprintf("%s/api/v1/jobs/%d/restart", i.URL, id)
+	return i.restartJobs(ctx, rurl, url.Values{}, opts)
+}
+
+/* Restart the given set of jobs, e.g. a whole dependency cluster. Returns the mapping of the original jobs to the restarted ones */
+func (i *Instance) RestartJobs(ids []int64, opts RestartOptions) (RestartResult, error) {
+	return i.RestartJobsContext(context.Background(), ids, opts)
+}
+
+func (i *Instance) RestartJobsContext(ctx context.Context, ids []int64, opts RestartOptions) (RestartResult, error) {
+	if len(ids) == 0 {
+		return RestartResult{Jobs: make(map[int64]int64, 0)}, nil
+	}
+	rurl := fmt.Sprintf("%s/api/v1/jobs/restart", i.URL)
+	params := url.Values{}
+	for _, id := range ids {
+		params.Add("jobs", fmt.Sprintf("%d", id))
+	}
+	return i.restartJobs(ctx, rurl, params, opts)
+}
+
+/* Cancel the given job */
+func (i *Instance) CancelJob(id int64) error {
+	return i.CancelJobContext(context.Background(), id)
+}
+
+func (i *Instance) CancelJobContext(ctx context.Context, id int64) error {
+	rurl := fmt.Sprintf("%s/api/v1/jobs/%d/cancel", i.URL, id)
+	buf, err := i.post(ctx, rurl, nil)
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	return err
+}
+
+/* Cancel the given set of jobs. Stops at the first job that cannot be cancelled */
+func (i *Instance) CancelJobs(ids []int64) error {
+	return i.CancelJobsContext(context.Background(), ids)
+}
+
+func (i *Instance) CancelJobsContext(ctx context.Context, ids []int64) error {
+	for _, id := range ids {
+		if err := i.CancelJobContext(ctx, id); err != nil {
+			return fmt.Errorf("job %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+/* Duplicate (clone) the given job. Returns the mapping of the original jobs to the new ones, which includes dependent jobs */
+func (i *Instance) DuplicateJob(id int64) (map[int64]int64, error) {
+	return i.DuplicateJobContext(context.Background(), id)
+}
+
+func (i *Instance) DuplicateJobContext(ctx context.Context, id int64) (map[int64]int64, error) {
+	rurl := fmt.Sprintf("%s/api/v1/jobs/%d/duplicate", i.URL, id)
+	buf, err := i.post(ctx, rurl, nil)
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	if err != nil {
+		return make(map[int64]int64, 0), err
+	}
+	// Result: {"id":124,"result":[{"123":124}]}
+	var obj struct {
+		ID     int64              `json:"id"`
+		Result []map[string]int64 `json:"result"`
+	}
+	if err := json.Unmarshal(buf, &obj); err != nil {
+		return make(map[int64]int64, 0), err
+	}
+	jobs, err := parseJobMapping(obj.Result)
+	if err != nil {
+		return jobs, err
+	}
+	if _, ok := jobs[id]; !ok && obj.ID > 0 {
+		jobs[id] = obj.ID
+	}
+	return jobs, nil
+}
+
+/* Duplicate (clone) the given set of jobs. Returns the mapping of all original jobs to the new ones */
+func (i *Instance) DuplicateJobs(ids []int64) (map[int64]int64, error) {
+	return i.DuplicateJobsContext(context.Background(), ids)
+}
+
+func (i *Instance) DuplicateJobsContext(ctx context.Context, ids []int64) (map[int64]int64, error) {
+	ret := make(map[int64]int64, 0)
+	for _, id := range ids {
+		// Jobs of the same cluster are duplicated together with the first one
+		if _, ok := ret[id]; ok {
+			continue
+		}
+		jobs, err := i.DuplicateJobContext(ctx, id)
+		if err != nil {
+			return ret, fmt.Errorf("job %d: %w", id, err)
+		}
+		for old, id := range jobs {
+			ret[old] = id
+		}
+	}
+	return ret, nil
+}
+
+/* Set the priority of the given job */
+func (i *Instance) SetJobPriority(id int64, priority int) error {
+	return i.SetJobPriorityContext(context.Background(), id, priority)
+}
+
+func (i *Instance) SetJobPriorityContext(ctx context.Context, id int64, priority int) error {
+	rurl := fmt.Sprintf("%s/api/v1/jobs/%d/prio", i.URL, id)
+	params := url.Values{}
+	params.Add("prio", fmt.Sprintf("%d", priority))
+	buf, err := i.post(ctx, rurl, []byte(params.Encode()))
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	return err
+}
+
+/* Set the priority of the given set of jobs. Stops at the first job that fails */
+func (i *Instance) SetJobsPriority(ids []int64, priority int) error {
+	return i.SetJobsPriorityContext(context.Background(), ids, priority)
+}
+
+func (i *Instance) SetJobsPriorityContext(ctx context.Context, ids []int64, priority int) error {
+	for _, id := range ids {
+		if err := i.SetJobPriorityContext(ctx, id, priority); err != nil {
+			return fmt.Errorf("job %d: %w", id, err)
+		}
+	}
+	return nil
+}
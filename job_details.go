@@ -0,0 +1,144 @@
+package gopenqa
+
+import (
+	"context"
+	"fmt"
+)
+
+/* Result of a test module including its steps, as shown on the job details page */
+type ModuleResult struct {
+	Name           string       `json:"name"`
+	Category       string       `json:"category"`
+	Result         string       `json:"result"` // e.g. passed, failed, softfailed, skipped or none
+	Fatal          FlexBool     `json:"fatal"`
+	Important      FlexBool     `json:"important"`
+	Milestone      FlexBool     `json:"milestone"`
+	AlwaysRollback FlexBool     `json:"always_rollback"`
+	ExecutionTime  string       `json:"execution_time"`
+	Details        []StepResult `json:"details"` // Steps of the module
+}
+
+/* Single step of a test module, e.g. a needle match, a screenshot or a text result */
+type StepResult struct {
+	Num        int           `json:"num"`
+	Result     string        `json:"result"` // ok, fail, softfail or unk
+	Title      string        `json:"title"`
+	Screenshot string        `json:"screenshot"` // File name of the screenshot, if any
+	Text       string        `json:"text"`       // File name of the text result, if any
+	TextData   string        `json:"text_data"`  // Content of the text result
+	Needle     string        `json:"needle"`     // Name of the matching needle
+	Needles    []NeedleMatch `json:"needles"`    // Candidate needles, that have been considered
+	Tags       []string      `json:"tags"`       // Needle tags the step was looking for
+}
+
+/* Candidate needle of a step */
+type NeedleMatch struct {
+	Name  string  `json:"name"`
+	Error float64 `json:"error"` // Match error as reported by os-autoinst, 0 is a perfect match
+}
+
+/* Failed step of a job together with the module it belongs to */
+type FailedStep struct {
+	Module string
+	Step   StepResult
+}
+
+/* Fetch the details of a job, including the test module results and result files */
+func (i *Instance) GetJobDetails(id int64) (Job, error) {
+	return i.GetJobDetailsContext(context.Background(), id)
+}
+
+func (i *Instance) GetJobDetailsContext(ctx context.Context, id int64) (Job, error) {
+	url := fmt.Sprintf("%s/api/v1/jobs/%d/details", i.URL, id)
+	return i.fetchJob(ctx, url)
+}
+
+/* HasFlag returns true if the given flag ("fatal", "important", "milestone" or "always_rollback") is set */
+func (m *ModuleResult) HasFlag(flag string) bool {
+	switch flag {
+	case "fatal":
+		return bool(m.Fatal)
+	case "important":
+		return bool(m.Important)
+	case "milestone":
+		return bool(m.Milestone)
+	case "always_rollback":
+		return bool(m.AlwaysRollback)
+	}
+	return false
+}
+
+/* IsFailed returns true if the module has failed */
+func (m *ModuleResult) IsFailed() bool {
+	return m.Result == "failed"
+}
+
+/* FailedSteps returns the failed steps of the module */
+func (m *ModuleResult) FailedSteps() []StepResult {
+	ret := make([]StepResult, 0)
+	for _, step := range m.Details {
+		if step.Result == "fail" {
+			ret = append(ret, step)
+		}
+	}
+	return ret
+}
+
+/* IsNeedleMismatch returns true if the step is a failed needle assertion */
+func (s *StepResult) IsNeedleMismatch() bool {
+	return s.Result == "fail" && len(s.Tags) > 0
+}
+
+/* ModuleResults returns the results of all test modules in execution order
+ * If the job has been fetched without details, the results are derived from the module list and contain no steps
+ */
+func (j *Job) ModuleResults() []ModuleResult {
+	if len(j.TestResults) > 0 {
+		return j.TestResults
+	}
+	ret := make([]ModuleResult, 0, len(j.Modules))
+	for _, module := range j.Modules {
+		ret = append(ret, ModuleResult{
+			Name:           module.Name,
+			Category:       module.Category,
+			Result:         module.Result,
+			Fatal:          FlexBool(module.HasFlag("fatal")),
+			Important:      FlexBool(module.HasFlag("important")),
+			Milestone:      FlexBool(module.HasFlag("milestone")),
+			AlwaysRollback: FlexBool(module.HasFlag("always_rollback")),
+		})
+	}
+	return ret
+}
+
+/* FirstFailedModule returns the first module that failed. Returns false, if no module failed */
+func (j *Job) FirstFailedModule() (ModuleResult, bool) {
+	for _, module := range j.ModuleResults() {
+		if module.IsFailed() {
+			return module, true
+		}
+	}
+	return ModuleResult{}, false
+}
+
+/* FailedSteps returns all failed steps of the job in execution order. Requires the job details */
+func (j *Job) FailedSteps() []FailedStep {
+	ret := make([]FailedStep, 0)
+	for _, module := range j.TestResults {
+		for _, step := range module.FailedSteps() {
+			ret = append(ret, FailedStep{Module: module.Name, Step: step})
+		}
+	}
+	return ret
+}
+
+/* FailedNeedles returns all steps, where no needle matched the expected tags. Requires the job details */
+func (j *Job) FailedNeedles() []FailedStep {
+	ret := make([]FailedStep, 0)
+	for _, failed := range j.FailedSteps() {
+		if failed.Step.IsNeedleMismatch() {
+			ret = append(ret, failed)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,117 @@
+package gopenqa
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+/* Result file of a job, e.g. autoinst-log.txt, serial0.txt, vars.json, worker-log.txt or the video */
+type ResultFile struct {
+	Name     string // File name
+	Uploaded bool   // true for files uploaded by the test itself via upload_logs
+	URL      string // Download URL
+}
+
+/* List all result files of the given job */
+func (i *Instance) GetJobResultFiles(id int64) ([]ResultFile, error) {
+	return i.GetJobResultFilesContext(context.Background(), id)
+}
+
+func (i *Instance) GetJobResultFilesContext(ctx context.Context, id int64) ([]ResultFile, error) {
+	ret := make([]ResultFile, 0)
+	job, err := i.GetJobDetailsContext(ctx, id)
+	if err != nil {
+		return ret, err
+	}
+	for _, name := range job.Logs {
+		ret = append(ret, ResultFile{Name: name, URL: i.jobFileURL(id, name)})
+	}
+	for _, name := range job.ULogs {
+		ret = append(ret, ResultFile{Name: name, Uploaded: true, URL: i.jobFileURL(id, name)})
+	}
+	return ret, nil
+}
+
+func (i *Instance) jobFileURL(id int64, name string) string {
+	return fmt.Sprintf("%s/tests/%d/file/%s", i.URL, id, url.PathEscape(name))
+}
+
+/* Download the given result file of a job. The file is streamed and the caller needs to close the returned reader */
+func (i *Instance) DownloadJobFile(id int64, name string) (io.ReadCloser, error) {
+	return i.DownloadJobFileContext(context.Background(), id, name)
+}
+
+func (i *Instance) DownloadJobFileContext(ctx context.Context, id int64, name string) (io.ReadCloser, error) {
+	r, err := i.stream(ctx, "GET", i.jobFileURL(id, name), nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.Body, nil
+}
+
+/* Download all result files of the given job into the given directory, which is created if necessary
+ * Subdirectories of the file names are kept. Existing files are overwritten. Returns the paths of the written files
+ */
+func (i *Instance) MirrorJobFiles(id int64, dir string) ([]string, error) {
+	return i.MirrorJobFilesContext(context.Background(), id, dir)
+}
+
+func (i *Instance) MirrorJobFilesContext(ctx context.Context, id int64, dir string) ([]string, error) {
+	written := make([]string, 0)
+	files, err := i.GetJobResultFilesContext(ctx, id)
+	if err != nil {
+		return written, err
+	}
+	// Never trust file names from remote. All paths must be within dir and unique
+	paths := make([]string, 0, len(files))
+	known := make(map[string]bool, 0)
+	for _, file := range files {
+		name := filepath.Clean(filepath.FromSlash(file.Name))
+		if !filepath.IsLocal(name) {
+			return written, fmt.Errorf("invalid file name: %s", file.Name)
+		}
+		path := filepath.Join(dir, name)
+		if known[path] {
+			return written, fmt.Errorf("duplicate file name: %s", file.Name)
+		}
+		known[path] = true
+		paths = append(paths, path)
+	}
+	for n, file := range files {
+		if err := os.MkdirAll(filepath.Dir(paths[n]), 0755); err != nil {
+			return written, err
+		}
+		if err := i.downloadJobFile(ctx, id, file.Name, paths[n]); err != nil {
+			return written, err
+		}
+		written = append(written, paths[n])
+	}
+	return written, nil
+}
+
+// downloadJobFile downloads the given result file to path. The file is first written to a temporary file to not leave partial files behind
+func (i *Instance) downloadJobFile(ctx context.Context, id int64, name string, path string) error {
+	body, err := i.DownloadJobFileContext(ctx, id, name)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
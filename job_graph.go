@@ -0,0 +1,357 @@
+package gopenqa
+
+import (
+	"context"
+	"sort"
+)
+
+/* Type of a dependency between two jobs, as used by openQA for the children and parents of a job */
+type DependencyType string
+
+const (
+	DependencyChained         DependencyType = "Chained"
+	DependencyDirectlyChained DependencyType = "Directly chained"
+	DependencyParallel        DependencyType = "Parallel"
+)
+
+/* Dependency between two jobs. The child job depends on the parent job */
+type JobDependency struct {
+	Parent int64
+	Child  int64
+	Type   DependencyType
+}
+
+/* JobGraph is the dependency cluster of a job, i.e. all jobs that are connected to it via parents or children */
+type JobGraph struct {
+	Root  int64         // ID of the job the graph has been created for
+	Jobs  map[int64]Job // All jobs of the cluster by their ID
+	Edges []JobDependency
+	known map[JobDependency]bool
+}
+
+func newJobGraph(root int64) JobGraph {
+	return JobGraph{Root: root, Jobs: make(map[int64]Job, 0), Edges: make([]JobDependency, 0), known: make(map[JobDependency]bool, 0)}
+}
+
+// dependencies returns the IDs of the given children or parents struct grouped by their dependency type
+func dependencies(c Children) map[DependencyType][]int64 {
+	return map[DependencyType][]int64{
+		DependencyChained:         c.Chained,
+		DependencyDirectlyChained: c.DirectlyChained,
+		DependencyParallel:        c.Parallel,
+	}
+}
+
+func (g *JobGraph) addEdge(parent int64, child int64, depType DependencyType) {
+	edge := JobDependency{Parent: parent, Child: child, Type: depType}
+	if g.known == nil {
+		g.known = make(map[JobDependency]bool, 0)
+	}
+	if parent == child || g.known[edge] {
+		return
+	}
+	g.known[edge] = true
+	g.Edges = append(g.Edges, edge)
+}
+
+/* NewJobGraph creates a graph from the given jobs without fetching anything
+ * Only dependencies between the given jobs are part of the graph. The root is the first job
+ */
+func NewJobGraph(jobs []Job) JobGraph {
+	var root int64
+	if len(jobs) > 0 {
+		root = jobs[0].ID
+	}
+	g := newJobGraph(root)
+	for _, job := range jobs {
+		g.Jobs[job.ID] = job
+	}
+	for _, job := range jobs {
+		for depType, ids := range dependencies(job.Children) {
+			for _, id := range ids {
+				if _, ok := g.Jobs[id]; ok {
+					g.addEdge(job.ID, id, depType)
+				}
+			}
+		}
+		for depType, ids := range dependencies(job.Parents) {
+			for _, id := range ids {
+				if _, ok := g.Jobs[id]; ok {
+					g.addEdge(id, job.ID, depType)
+				}
+			}
+		}
+	}
+	g.sortEdges()
+	return g
+}
+
+/* Fetch the full dependency cluster of the given job recursively
+ * If follow is set, cloned jobs are replaced by their most recent clone
+ */
+func (i *Instance) GetJobGraph(id int64, follow bool) (JobGraph, error) {
+	return i.GetJobGraphContext(context.Background(), id, follow)
+}
+
+func (i *Instance) GetJobGraphContext(ctx context.Context, id int64, follow bool) (JobGraph, error) {
+	g := newJobGraph(id)
+	resolved := make(map[int64]int64, 0) // Fetched job ID by requested job ID, differs for followed clones
+	queue := make([]int64, 0)
+
+	// resolve fetches the given job, if not yet done, and returns its (possibly followed) ID
+	resolve := func(id int64) (int64, error) {
+		if rid, ok := resolved[id]; ok {
+			return rid, nil
+		}
+		var job Job
+		var err error
+		if follow {
+			job, err = i.GetJobFollowContext(ctx, id)
+		} else {
+			job, err = i.GetJobContext(ctx, id)
+		}
+		if err != nil {
+			return 0, err
+		}
+		resolved[id] = job.ID
+		if _, ok := g.Jobs[job.ID]; !ok {
+			resolved[job.ID] = job.ID
+			g.Jobs[job.ID] = job
+			queue = append(queue, job.ID)
+		}
+		return job.ID, nil
+	}
+
+	root, err := resolve(id)
+	if err != nil {
+		return g, err
+	}
+	g.Root = root
+	// Breadth-first search. Every job is only fetched and visited once, so cycles are no problem
+	for len(queue) > 0 {
+		job := g.Jobs[queue[0]]
+		queue = queue[1:]
+		for depType, ids := range dependencies(job.Children) {
+			for _, id := range ids {
+				child, err := resolve(id)
+				if err != nil {
+					return g, err
+				}
+				g.addEdge(job.ID, child, depType)
+			}
+		}
+		for depType, ids := range dependencies(job.Parents) {
+			for _, id := range ids {
+				parent, err := resolve(id)
+				if err != nil {
+					return g, err
+				}
+				g.addEdge(parent, job.ID, depType)
+			}
+		}
+	}
+	g.sortEdges()
+	return g, nil
+}
+
+// sortEdges sorts the edges to get reproducible results
+func (g *JobGraph) sortEdges() {
+	sort.Slice(g.Edges, func(a, b int) bool {
+		ea, eb := g.Edges[a], g.Edges[b]
+		if ea.Parent != eb.Parent {
+			return ea.Parent < eb.Parent
+		}
+		if ea.Child != eb.Child {
+			return ea.Child < eb.Child
+		}
+		return ea.Type < eb.Type
+	})
+}
+
+/* IDs returns the IDs of all jobs in the graph in ascending order */
+func (g *JobGraph) IDs() []int64 {
+	ids := make([]int64, 0, len(g.Jobs))
+	for id := range g.Jobs {
+		ids = append(ids, id)
+	}
+	sortIDs(ids)
+	return ids
+}
+
+/* Parents returns the direct dependencies of the given job */
+func (g *JobGraph) Parents(id int64) []JobDependency {
+	ret := make([]JobDependency, 0)
+	for _, edge := range g.Edges {
+		if edge.Child == id {
+			ret = append(ret, edge)
+		}
+	}
+	return ret
+}
+
+/* Children returns the direct dependents of the given job */
+func (g *JobGraph) Children(id int64) []JobDependency {
+	ret := make([]JobDependency, 0)
+	for _, edge := range g.Edges {
+		if edge.Parent == id {
+			ret = append(ret, edge)
+		}
+	}
+	return ret
+}
+
+/* Roots returns the jobs without parents */
+func (g *JobGraph) Roots() []int64 {
+	ret := make([]int64, 0)
+	for _, id := range g.IDs() {
+		if len(g.Parents(id)) == 0 {
+			ret = append(ret, id)
+		}
+	}
+	return ret
+}
+
+// walk returns all jobs reachable from the given job in the given direction
+func (g *JobGraph) walk(id int64, up bool) []int64 {
+	visited := map[int64]bool{id: true}
+	ret := make([]int64, 0)
+	queue := []int64{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Edges {
+			var next int64
+			if up && edge.Child == current {
+				next = edge.Parent
+			} else if !up && edge.Parent == current {
+				next = edge.Child
+			} else {
+				continue
+			}
+			if !visited[next] {
+				visited[next] = true
+				ret = append(ret, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	sortIDs(ret)
+	return ret
+}
+
+/* Ancestors returns all jobs the given job depends on, directly or indirectly */
+func (g *JobGraph) Ancestors(id int64) []int64 {
+	return g.walk(id, true)
+}
+
+/* Descendants returns all jobs that depend on the given job, directly or indirectly */
+func (g *JobGraph) Descendants(id int64) []int64 {
+	return g.walk(id, false)
+}
+
+/* Parallel returns the jobs that run in parallel with the given job, i.e. its parallel cluster without the job itself */
+func (g *JobGraph) Parallel(id int64) []int64 {
+	visited := map[int64]bool{id: true}
+	queue := []int64{id}
+	ret := make([]int64, 0)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Edges {
+			if edge.Type != DependencyParallel {
+				continue
+			}
+			var next int64
+			if edge.Parent == current {
+				next = edge.Child
+			} else if edge.Child == current {
+				next = edge.Parent
+			} else {
+				continue
+			}
+			if !visited[next] {
+				visited[next] = true
+				ret = append(ret, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	sortIDs(ret)
+	return ret
+}
+
+/* Blockers returns the jobs that prevent the given job from running, e.g. to explain its blocked_by_id
+ * Those are all chained or directly chained ancestors, which have not reached a final state yet.
+ * Parallel parents are no blockers themselves, as they run together with the job, but their ancestors are
+ */
+func (g *JobGraph) Blockers(id int64) []int64 {
+	visited := map[int64]bool{id: true}
+	blocking := make(map[int64]bool, 0)
+	queue := []int64{id}
+	ret := make([]int64, 0)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Parents(current) {
+			if edge.Type != DependencyParallel && !blocking[edge.Parent] {
+				if job, ok := g.Jobs[edge.Parent]; ok && !(&JobState{State: job.State}).IsFinal() {
+					blocking[edge.Parent] = true
+					ret = append(ret, edge.Parent)
+				}
+			}
+			if !visited[edge.Parent] {
+				visited[edge.Parent] = true
+				queue = append(queue, edge.Parent)
+			}
+		}
+	}
+	sortIDs(ret)
+	return ret
+}
+
+/* TopologicalOrder returns all jobs ordered such that every job comes after the jobs it depends on
+ * Returns ErrDependencyCycle if the dependencies contain a cycle
+ */
+func (g *JobGraph) TopologicalOrder() ([]int64, error) {
+	// Kahn's algorithm. Jobs without dependencies are taken in ascending order for reproducible results
+	indegree := make(map[int64]int, len(g.Jobs))
+	for id := range g.Jobs {
+		indegree[id] = 0
+	}
+	for _, edge := range g.Edges {
+		indegree[edge.Child]++
+		if _, ok := indegree[edge.Parent]; !ok {
+			indegree[edge.Parent] = 0
+		}
+	}
+	ready := make([]int64, 0)
+	for id, n := range indegree {
+		if n == 0 {
+			ready = append(ready, id)
+		}
+	}
+	ret := make([]int64, 0, len(indegree))
+	for len(ready) > 0 {
+		sortIDs(ready)
+		id := ready[0]
+		ready = ready[1:]
+		ret = append(ret, id)
+		for _, edge := range g.Edges {
+			if edge.Parent != id {
+				continue
+			}
+			indegree[edge.Child]--
+			if indegree[edge.Child] == 0 {
+				ready = append(ready, edge.Child)
+			}
+		}
+	}
+	if len(ret) != len(indegree) {
+		return ret, ErrDependencyCycle
+	}
+	return ret, nil
+}
+
+func sortIDs(ids []int64) {
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+}
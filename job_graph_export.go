@@ -0,0 +1,119 @@
+package gopenqa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// stateColor returns the fill color for a job state as returned by Job.JobState(), similar to the openQA web UI
+func stateColor(state string) string {
+	switch state {
+	case "passed":
+		return "#5cb85c"
+	case "softfailed":
+		return "#ecbf32"
+	case "failed", "parallel_failed":
+		return "#d9534f"
+	case "incomplete", "timeout_exceeded":
+		return "#b94a48"
+	case "running", "uploading", "assigned", "setup":
+		return "#428bca"
+	case "scheduled", "blocked":
+		return "#dddddd"
+	case "user_cancelled", "cancelled", "obsoleted", "skipped", "parallel_restarted", "user_restarted":
+		return "#999999"
+	}
+	return "#ffffff"
+}
+
+// label returns the text to display for a job node
+func (g *JobGraph) label(id int64) string {
+	job, ok := g.Jobs[id]
+	if !ok {
+		return fmt.Sprintf("%d", id)
+	}
+	name := job.Test
+	if job.Settings.Machine != "" {
+		name += "@" + job.Settings.Machine
+	}
+	label := fmt.Sprintf("%d %s\n%s", id, name, job.JobState())
+	if job.State == "done" && job.Tfinished != "" {
+		label += "\n" + job.FinishedAt().Format("15:04:05")
+	}
+	return label
+}
+
+// state returns the state of the given job or an empty string, if the job is unknown
+func (g *JobGraph) state(id int64) string {
+	if job, ok := g.Jobs[id]; ok {
+		return job.JobState()
+	}
+	return ""
+}
+
+// dotQuote returns the given text as quoted DOT string. Only quotes and backslashes are escaped, newlines become line breaks
+func dotQuote(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	text = strings.ReplaceAll(text, "\"", "\\\"")
+	text = strings.ReplaceAll(text, "\n", "\\n")
+	return "\"" + text + "\""
+}
+
+/* DOT renders the graph in the Graphviz DOT language
+ * Nodes are filled according to their state, edges are labelled with the dependency type
+ */
+func (g *JobGraph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("digraph \"job_%d\" {\n", g.Root))
+	sb.WriteString("\tnode [shape=box, style=filled];\n")
+	for _, id := range g.IDs() {
+		attrs := fmt.Sprintf("label=%s, fillcolor=%s", dotQuote(g.label(id)), dotQuote(stateColor(g.state(id))))
+		if job := g.Jobs[id]; job.Link != "" {
+			attrs += fmt.Sprintf(", URL=%s", dotQuote(job.Link))
+		}
+		if id == g.Root {
+			attrs += ", penwidth=3"
+		}
+		sb.WriteString(fmt.Sprintf("\t%d [%s];\n", id, attrs))
+	}
+	for _, edge := range g.Edges {
+		attrs := fmt.Sprintf("label=%s", dotQuote(string(edge.Type)))
+		switch edge.Type {
+		case DependencyDirectlyChained:
+			attrs += ", style=bold"
+		case DependencyParallel:
+			attrs += ", style=dashed, dir=none"
+		}
+		sb.WriteString(fmt.Sprintf("\t%d -> %d [%s];\n", edge.Parent, edge.Child, attrs))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+/* Mermaid renders the graph as Mermaid flowchart
+ * Nodes are filled according to their state, edges are labelled with the dependency type
+ */
+func (g *JobGraph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+	for _, id := range g.IDs() {
+		// Mermaid doesn't support newlines or quotes within labels
+		label := strings.ReplaceAll(g.label(id), "\n", "<br>")
+		label = strings.ReplaceAll(label, "\"", "#quot;")
+		sb.WriteString(fmt.Sprintf("\tjob%d[\"%s\"]\n", id, label))
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		switch edge.Type {
+		case DependencyDirectlyChained:
+			arrow = "==>"
+		case DependencyParallel:
+			arrow = "-.-"
+		}
+		sb.WriteString(fmt.Sprintf("\tjob%d %s|%s| job%d\n", edge.Parent, arrow, edge.Type, edge.Child))
+	}
+	for _, id := range g.IDs() {
+		sb.WriteString(fmt.Sprintf("\tstyle job%d fill:%s\n", id, stateColor(g.state(id))))
+	}
+	return sb.String()
+}
@@ -0,0 +1,234 @@
+package gopenqa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Default number of jobs per page for the job iterator
+const defaultPageSize = 100
+
+/* JobIterator pages lazily through the results of a /api/v1/jobs query
+ * Only the current page is kept in memory. Each page is read completely before its first job is returned,
+ * so no connection is held while the caller processes the jobs and other requests on the instance don't block.
+ *
+ *	it := instance.IterateJobs(params, 100)
+ *	defer it.Close()
+ *	for it.Next() {
+ *		job := it.Job()
+ *	}
+ *	if err := it.Err(); err != nil { ... }
+ */
+type JobIterator struct {
+	instance *Instance
+	ctx      context.Context
+	params   url.Values
+	pageSize int
+	offset   int
+	page     string         // URL of the page to fetch next, empty if there are no more pages
+	link     string         // Link to the next page as given by openQA in the Link header
+	jobs     []Job          // Jobs of the current page
+	previous map[int64]bool // IDs of the jobs of the previous page
+	pos      int            // Position of the next job within the current page
+	loaded   bool           // true if the current page has been loaded
+	total    int            // Number of jobs returned in total
+	max      int            // Maximum number of jobs, 0 means no limit
+	job      Job
+	err      error
+}
+
+/* Iterate over all jobs matching the given parameters, fetching pageSize jobs per request
+ * See GetOverview for the usage of the parameters. The iterator must be closed after usage
+ */
+func (i *Instance) IterateJobs(params map[string]string, pageSize int) *JobIterator {
+	return i.IterateJobsContext(context.Background(), params, pageSize)
+}
+
+func (i *Instance) IterateJobsContext(ctx context.Context, params map[string]string, pageSize int) *JobIterator {
+	return i.IterateJobsQueryContext(ctx, jobQueryFromParams(params), pageSize)
+}
+
+// iterateJobs creates a job iterator for the given parameters. max limits the total number of jobs, 0 means no limit
+func (i *Instance) iterateJobs(ctx context.Context, params url.Values, pageSize int, max int) *JobIterator {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if max > 0 && max < pageSize {
+		pageSize = max
+	}
+	it := &JobIterator{instance: i, ctx: ctx, params: params, pageSize: pageSize, max: max}
+	it.page = it.pageURL()
+	return it
+}
+
+// pageURL returns the URL of the page at the current offset
+func (it *JobIterator) pageURL() string {
+	params := url.Values{}
+	for k, v := range it.params {
+		params[k] = v
+	}
+	params.Set("limit", fmt.Sprintf("%d", it.pageSize))
+	params.Set("offset", fmt.Sprintf("%d", it.offset))
+	return fmt.Sprintf("%s/api/v1/jobs?%s", it.instance.URL, params.Encode())
+}
+
+/* Next advances to the next job. Returns false when there are no more jobs or an error occurred */
+func (it *JobIterator) Next() bool {
+	for it.err == nil {
+		if it.max > 0 && it.total >= it.max {
+			it.Close()
+			return false
+		}
+		if !it.loaded {
+			if it.page == "" {
+				return false
+			}
+			if err := it.openPage(); err != nil {
+				it.err = err
+				return false
+			}
+			if err := it.checkProgress(); err != nil {
+				it.err = err
+				it.Close()
+				return false
+			}
+			continue
+		}
+		if it.pos < len(it.jobs) {
+			it.job = it.jobs[it.pos]
+			it.pos++
+			it.total++
+			return true
+		}
+		// End of the current page. Continue with the next one, if there are more jobs
+		count := len(it.jobs)
+		it.closePage()
+		it.offset += count
+		if count == 0 {
+			it.page = ""
+		} else if it.link != "" {
+			it.page = it.link
+		} else if count >= it.pageSize {
+			it.page = it.pageURL()
+		} else {
+			it.page = ""
+		}
+	}
+	return false
+}
+
+/* Job returns the current job */
+func (it *JobIterator) Job() Job {
+	return it.job
+}
+
+/* Err returns the error that stopped the iteration, if any */
+func (it *JobIterator) Err() error {
+	return it.err
+}
+
+/* Close stops the iteration and releases the current page */
+func (it *JobIterator) Close() {
+	it.closePage()
+	it.page = ""
+}
+
+// openPage requests the next page and reads all of its jobs
+func (it *JobIterator) openPage() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+	r, err := it.instance.stream(it.ctx, "GET", it.page, nil)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	it.link = parseLinkNext(r.Header.Get("Link"))
+	it.jobs = make([]Job, 0, it.pageSize)
+	it.pos = 0
+	it.loaded = true
+	dec := json.NewDecoder(r.Body)
+	// Expected result structure: {"jobs":[...]}, other keys are skipped
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return ErrInvalidResponse
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key, ok := tok.(string); ok && key == "jobs" {
+			if tok, err := dec.Token(); err != nil {
+				return err
+			} else if tok != json.Delim('[') {
+				return ErrInvalidResponse
+			}
+			for dec.More() {
+				var job Job
+				if err := dec.Decode(&job); err != nil {
+					return err
+				}
+				job.applyInstance(it.instance)
+				it.jobs = append(it.jobs, job)
+			}
+			return nil
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	// No jobs in this page
+	return nil
+}
+
+// checkProgress ensures that the current page contains jobs that were not on the previous page
+// Servers that ignore the offset would otherwise return the same page over and over again
+func (it *JobIterator) checkProgress() error {
+	ids := make(map[int64]bool, len(it.jobs))
+	progress := false
+	for _, job := range it.jobs {
+		ids[job.ID] = true
+		if !it.previous[job.ID] {
+			progress = true
+		}
+	}
+	if len(it.jobs) > 0 && !progress {
+		return fmt.Errorf("%w: %s repeats the jobs of the previous page", ErrInvalidResponse, it.page)
+	}
+	it.previous = ids
+	return nil
+}
+
+func (it *JobIterator) closePage() {
+	it.jobs = nil
+	it.pos = 0
+	it.loaded = false
+}
+
+// parseLinkNext returns the URL with rel="next" from a Link header, or an empty string if not present
+func parseLinkNext(header string) string {
+	// Example: <https://openqa.opensuse.org/api/v1/jobs?limit=100&offset=100>; rel="next", <...>; rel="prev"
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range parts[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if param == `rel="next"` || param == "rel=next" {
+				return target[1 : len(target)-1]
+			}
+		}
+	}
+	return ""
+}
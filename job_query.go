@@ -0,0 +1,186 @@
+package gopenqa
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+/* JobQuery is a typed query for jobs. Empty fields are not added to the query
+ * Multiple values for the same field are passed multiple times, which openQA treats as alternatives
+ */
+type JobQuery struct {
+	Distri  []string
+	Version []string
+	Flavor  []string
+	Arch    []string
+	Machine []string
+	Build   []string
+	Groups  []int // Job group IDs
+	State   []string
+	Result  []string
+	Test    []string
+	Latest  bool       // Only the latest job of each scenario
+	Limit   int        // Maximum number of returned jobs, 0 means no limit
+	Extra   url.Values // Additional parameters, added as they are
+}
+
+/* Values returns the query as url values */
+func (q *JobQuery) Values() url.Values {
+	values := url.Values{}
+	add := func(key string, vals []string) {
+		for _, v := range vals {
+			values.Add(key, v)
+		}
+	}
+	add("distri", q.Distri)
+	add("version", q.Version)
+	add("flavor", q.Flavor)
+	add("arch", q.Arch)
+	add("machine", q.Machine)
+	add("build", q.Build)
+	for _, group := range q.Groups {
+		values.Add("groupid", fmt.Sprintf("%d", group))
+	}
+	add("state", q.State)
+	add("result", q.Result)
+	add("test", q.Test)
+	if q.Latest {
+		values.Set("latest", "1")
+	}
+	if q.Limit > 0 {
+		values.Set("limit", fmt.Sprintf("%d", q.Limit))
+	}
+	for k, v := range q.Extra {
+		add(k, v)
+	}
+	return values
+}
+
+/* Encode returns the query as escaped query string */
+func (q *JobQuery) Encode() string {
+	return q.Values().Encode()
+}
+
+// jobQueryListKeys are the parameters that openQA treats as comma separated lists
+var jobQueryListKeys = map[string]bool{"ids": true, "state": true, "result": true, "groupid": true, "group_id": true}
+
+// jobQueryFromParams converts the legacy parameter map to a JobQuery
+// Only values of jobQueryListKeys are split on commas, all other values are passed as they are
+func jobQueryFromParams(params map[string]string) JobQuery {
+	q := JobQuery{Extra: url.Values{}}
+	for k, arg := range params {
+		values := []string{arg}
+		if jobQueryListKeys[k] {
+			// openQA supports parameter arrays by passing them multiple times. We do this by splitting commas
+			values = strings.Split(arg, ",")
+		}
+		switch k {
+		case "distri":
+			q.Distri = append(q.Distri, values...)
+		case "version":
+			q.Version = append(q.Version, values...)
+		case "flavor":
+			q.Flavor = append(q.Flavor, values...)
+		case "arch":
+			q.Arch = append(q.Arch, values...)
+		case "machine":
+			q.Machine = append(q.Machine, values...)
+		case "build":
+			q.Build = append(q.Build, values...)
+		case "state":
+			q.State = append(q.State, values...)
+		case "result":
+			q.Result = append(q.Result, values...)
+		case "test":
+			q.Test = append(q.Test, values...)
+		case "latest":
+			q.Latest = arg == "1" || arg == "true"
+		case "limit":
+			if limit, err := strconv.Atoi(arg); err == nil {
+				q.Limit = limit
+			} else {
+				q.Extra.Add(k, arg)
+			}
+		case "groupid", "group_id":
+			for _, v := range values {
+				if group, err := strconv.Atoi(v); err == nil {
+					q.Groups = append(q.Groups, group)
+				} else {
+					q.Extra.Add(k, v)
+				}
+			}
+		default:
+			q.Extra[k] = append(q.Extra[k], values...)
+		}
+	}
+	return q
+}
+
+/* Query the job overview. Overview returns only the job id and name */
+func (i *Instance) GetOverviewQuery(q JobQuery) ([]Job, error) {
+	return i.GetOverviewQueryContext(context.Background(), q)
+}
+
+func (i *Instance) GetOverviewQueryContext(ctx context.Context, q JobQuery) ([]Job, error) {
+	url := fmt.Sprintf("%s/api/v1/jobs/overview", i.URL)
+	if params := q.Encode(); params != "" {
+		url += "?" + params
+	}
+	jobs, err := i.fetchJobs(ctx, url)
+	assignInstance(jobs, i)
+	return jobs, err
+}
+
+/* Get only the latest job per job group of the jobs matching the query
+ * The jobs are fetched with a single request, so openQA may truncate them. Use IterateJobsQuery to page through all jobs
+ */
+func (i *Instance) GetLatestJobsQuery(q JobQuery) ([]Job, error) {
+	return i.GetLatestJobsQueryContext(context.Background(), q)
+}
+
+func (i *Instance) GetLatestJobsQueryContext(ctx context.Context, q JobQuery) ([]Job, error) {
+	url := fmt.Sprintf("%s/api/v1/jobs", i.URL)
+	if params := q.Encode(); params != "" {
+		url += "?" + params
+	}
+	jobs, err := i.fetchJobsArray(ctx, url)
+	if err != nil {
+		return make([]Job, 0), err
+	}
+	// Now, get only the latest job per group_id
+	mapped := make(map[int]Job)
+	for _, job := range jobs {
+		// TODO: Filter job results, if given
+
+		// Only keep newer jobs (by ID) per group
+		if f, ok := mapped[job.GroupID]; ok {
+			if job.ID > f.ID {
+				mapped[job.GroupID] = job
+			}
+		} else {
+			mapped[job.GroupID] = job
+		}
+	}
+	// Make slice from map
+	ret := make([]Job, 0)
+	for _, v := range mapped {
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+/* Iterate over all jobs matching the query, fetching pageSize jobs per request. q.Limit limits the total number of jobs
+ * The iterator must be closed after usage
+ */
+func (i *Instance) IterateJobsQuery(q JobQuery, pageSize int) *JobIterator {
+	return i.IterateJobsQueryContext(context.Background(), q, pageSize)
+}
+
+func (i *Instance) IterateJobsQueryContext(ctx context.Context, q JobQuery, pageSize int) *JobIterator {
+	params := q.Values()
+	params.Del("limit") // set per page by the iterator
+	return i.iterateJobs(ctx, params, pageSize, q.Limit)
+}
@@ -1,11 +1,53 @@
 package gopenqa
 
+import (
+	"encoding/json"
+	"sort"
+)
+
 /* Job Template */
 type JobTemplate struct {
-	GroupName string    `json:"group_name"`
-	ID        int       `json:"id"`
-	Machine   Machine   `json:"machine"`
-	Priority  int       `json:"prio"`
-	Product   Product   `json:"product"`
-	TestSuite TestSuite `json:"test_suite"`
+	GroupName string            `json:"group_name"`
+	ID        int               `json:"id"`
+	Machine   Machine           `json:"machine"`
+	Priority  int               `json:"prio"`
+	Product   Product           `json:"product"`
+	TestSuite TestSuite         `json:"test_suite"`
+	Settings  map[string]string `json:"-"` // Settings of the job template itself
+}
+
+func (t *JobTemplate) UnmarshalJSON(data []byte) error {
+	type jobTemplate JobTemplate // Type without the UnmarshalJSON method to avoid recursion
+	var obj struct {
+		jobTemplate
+		// openQA returns the settings as list of key/value pairs, e.g. "settings":[{"key":"HDDSIZEGB","value":"40"}]
+		Settings json.RawMessage `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*t = JobTemplate(obj.jobTemplate)
+	t.Settings = make(map[string]string, 0)
+	if len(obj.Settings) == 0 || string(obj.Settings) == "null" {
+		return nil
+	}
+	var list []map[string]string
+	if err := json.Unmarshal(obj.Settings, &list); err == nil {
+		for _, setting := range list {
+			t.Settings[setting["key"]] = setting["value"]
+		}
+		return nil
+	}
+	return json.Unmarshal(obj.Settings, &t.Settings)
+}
+
+// MarshalJSON encodes the settings as list of key/value pairs, as openQA returns them
+func (t JobTemplate) MarshalJSON() ([]byte, error) {
+	type jobTemplate JobTemplate // Type without the MarshalJSON method to avoid recursion
+	settings := convertSettingsFrom(t.Settings)
+	sort.Slice(settings, func(a, b int) bool { return settings[a]["key"] < settings[b]["key"] })
+	return json.Marshal(struct {
+		jobTemplate
+		Settings []map[string]string `json:"settings"`
+	}{jobTemplate(t), settings})
 }
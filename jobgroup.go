@@ -13,16 +13,16 @@ type JobGroup struct {
 	Description      string `json:"description"`
 	BuildVersionSort int    `json:"build_version_sort"`
 	CarryOverBugrefs int    `json:"carry_over_bugrefs"`
-	DefaultPriority  int    `json:"default_priority`
-	// Disabled because of type mismatch in json
-	// Sometimes it's returned as int, sometimes as string and we cannot deal with that atm
-	//KeepImportantLogsInDays    int    `json:"keep_important_logs_in_days"`
-	//KeepImportantResultsInDays int    `json:"keep_important_results_in_days"`
-	//KeepLogsInDays             int    `json:"keep_logs_in_days"`
-	//KeepResultsInDays          int    `json:"keep_results_in_days"`
-	//SizeLimit                  int    `json:"size_limit_gb"` // Size limit in GB
-	SortOrder int    `json:"sort_order"`
-	Template  string `json:"template"`
+	DefaultPriority  int    `json:"default_priority"`
+	// openQA returns those sometimes as int and sometimes as string
+	// nil means not set, so that openQA uses its defaults when creating a group. 0 means "keep forever" or "no limit"
+	KeepImportantLogsInDays    *FlexInt `json:"keep_important_logs_in_days"`
+	KeepImportantResultsInDays *FlexInt `json:"keep_important_results_in_days"`
+	KeepLogsInDays             *FlexInt `json:"keep_logs_in_days"`
+	KeepResultsInDays          *FlexInt `json:"keep_results_in_days"`
+	SizeLimit                  *FlexInt `json:"size_limit_gb"` // Size limit in GB
+	SortOrder                  int      `json:"sort_order"`
+	Template                   string   `json:"template"`
 }
 
 func addIntIfNotZero(value int, name string, values *url.Values) {
@@ -31,6 +31,12 @@ func addIntIfNotZero(value int, name string, values *url.Values) {
 	}
 }
 
+func addIntIfSet(value *FlexInt, name string, values *url.Values) {
+	if value != nil {
+		values.Add(name, fmt.Sprintf("%d", *value))
+	}
+}
+
 /* Get www-form-urlencoded parameters of this Product */
 func (j *JobGroup) encodeWWW() string {
 	params := url.Values{}
@@ -44,11 +50,12 @@ func (j *JobGroup) encodeWWW() string {
 	addIntIfNotZero(j.BuildVersionSort, "build_version_sort", &params)
 	addIntIfNotZero(j.CarryOverBugrefs, "carry_over_bugrefs", &params)
 	addIntIfNotZero(j.DefaultPriority, "default_priority", &params)
-	//addIntIfNotZero(j.KeepImportantLogsInDays, "keep_important_logs_in_days", &params)
-	//addIntIfNotZero(j.KeepImportantResultsInDays, "keep_important_results_in_days", &params)
-	//addIntIfNotZero(j.KeepLogsInDays, "keep_logs_in_days", &params)
-	//addIntIfNotZero(j.KeepResultsInDays, "keep_results_in_days", &params)
-	//addIntIfNotZero(j.SizeLimit, "size_limit_gb", &params)
+	// Retention settings and the size limit are sent only if set, as 0 is a valid value
+	addIntIfSet(j.KeepImportantLogsInDays, "keep_important_logs_in_days", &params)
+	addIntIfSet(j.KeepImportantResultsInDays, "keep_important_results_in_days", &params)
+	addIntIfSet(j.KeepLogsInDays, "keep_logs_in_days", &params)
+	addIntIfSet(j.KeepResultsInDays, "keep_results_in_days", &params)
+	addIntIfSet(j.SizeLimit, "size_limit_gb", &params)
 	addIntIfNotZero(j.SortOrder, "sort_order", &params)
 	params.Add("template", j.Template)
 
@@ -0,0 +1,126 @@
+package gopenqa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/* FlexInt is an integer that accepts JSON numbers, numeric strings and null
+ * openQA returns some numeric fields sometimes as int, sometimes as string
+ */
+type FlexInt int
+
+func (f *FlexInt) UnmarshalJSON(data []byte) error {
+	i, err := parseFlexInt(data, strconv.IntSize)
+	if err != nil {
+		return err
+	}
+	*f = FlexInt(i)
+	return nil
+}
+
+func (f FlexInt) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(f))), nil
+}
+
+/* FlexInt64 is the 64-bit variant of FlexInt, e.g. for sizes in bytes, which exceed int on 32-bit platforms */
+type FlexInt64 int64
+
+func (f *FlexInt64) UnmarshalJSON(data []byte) error {
+	i, err := parseFlexInt(data, 64)
+	if err != nil {
+		return err
+	}
+	*f = FlexInt64(i)
+	return nil
+}
+
+func (f FlexInt64) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(f), 10)), nil
+}
+
+// parseFlexInt parses a JSON number, numeric string or null as integer that fits into the given number of bits
+func parseFlexInt(data []byte, bitSize int) (int64, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return 0, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return 0, err
+	}
+	switch v := value.(type) {
+	case float64:
+		// Integers are parsed exactly, as float64 cannot represent all of them
+		if i, err := strconv.ParseInt(string(data), 10, bitSize); err == nil {
+			return i, nil
+		}
+		return roundFlexInt(v, bitSize, data)
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return 0, nil
+		}
+		if i, err := strconv.ParseInt(v, 10, bitSize); err == nil {
+			return i, nil
+		}
+		if fl, err := strconv.ParseFloat(v, 64); err == nil {
+			return roundFlexInt(fl, bitSize, data)
+		}
+	}
+	return 0, fmt.Errorf("cannot parse %s as integer", string(data))
+}
+
+// roundFlexInt rounds the given float to an integer, if it fits into the given number of bits
+func roundFlexInt(value float64, bitSize int, data []byte) (int64, error) {
+	value = math.Round(value)
+	limit := math.Ldexp(1, bitSize-1)
+	if math.IsNaN(value) || value < -limit || value >= limit {
+		return 0, fmt.Errorf("cannot parse %s as integer", string(data))
+	}
+	return int64(value), nil
+}
+
+/* FlexBool is a boolean that accepts JSON booleans, numbers, strings and null
+ * openQA returns flags sometimes as boolean and sometimes as 0/1
+ */
+type FlexBool bool
+
+func (f *FlexBool) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*f = false
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*f = FlexBool(v)
+		return nil
+	case float64:
+		*f = v != 0
+		return nil
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			*f = false
+			return nil
+		}
+		if b, err := strconv.ParseBool(v); err == nil {
+			*f = FlexBool(b)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse %s as boolean", string(data))
+}
+
+func (f FlexBool) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatBool(bool(f))), nil
+}
@@ -3,6 +3,7 @@
 package gopenqa
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -99,6 +100,7 @@ type RabbitMQSubscription struct {
 	channel *amqp.Channel
 	key     string
 	obs     <-chan amqp.Delivery
+	pending *amqp.Delivery // Message received after the context was done. Returned by the next receive
 	mq      *RabbitMQ
 	con     *amqp.Connection // Keep a reference to the connection to check if it is still connected. This is necessary because mq can reconnect and therefore have another new mq.con instance
 }
@@ -110,15 +112,41 @@ func (sub *RabbitMQSubscription) Connected() bool {
 
 // Receive receives a raw non-empty RabbitMQ messages
 func (sub *RabbitMQSubscription) Receive() (amqp.Delivery, error) {
-	for msg, ok := <-sub.obs; ok; {
-		if len(msg.Body) > 0 {
+	return sub.ReceiveContext(context.Background())
+}
+
+// ReceiveContext receives a raw non-empty RabbitMQ message. Returns the error of the context, once it is done
+// No message is lost after the context is done, so the subscription can be used further
+func (sub *RabbitMQSubscription) ReceiveContext(ctx context.Context) (amqp.Delivery, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return amqp.Delivery{}, err
+		}
+		if sub.pending != nil {
+			msg := *sub.pending
+			sub.pending = nil
 			return msg, nil
 		}
+		select {
+		case <-ctx.Done():
+			return amqp.Delivery{}, ctx.Err()
+		case msg, ok := <-sub.obs:
+			if !ok {
+				if sub.mq == nil || sub.mq.closed || sub.con == nil || sub.con.IsClosed() {
+					return amqp.Delivery{}, fmt.Errorf("EOF")
+				}
+				return amqp.Delivery{}, fmt.Errorf("channel unexpectedly closed")
+			}
+			if len(msg.Body) > 0 {
+				// select chooses randomly, if the context is done and a message is available at the same time
+				if err := ctx.Err(); err != nil {
+					sub.pending = &msg
+					return amqp.Delivery{}, err
+				}
+				return msg, nil
+			}
+		}
 	}
-	if sub.mq == nil || sub.mq.closed || sub.con == nil || sub.con.IsClosed() {
-		return amqp.Delivery{}, fmt.Errorf("EOF")
-	}
-	return amqp.Delivery{}, fmt.Errorf("channel unexpectedly closed")
 }
 
 // ReceiveJob receives the next message and try to parse it as job
@@ -148,6 +176,12 @@ func (sub *RabbitMQSubscription) ReceiveJobStatus() (JobStatus, error) {
 	if err != nil {
 		return status, err
 	}
+	return parseJobStatus(d)
+}
+
+// parseJobStatus parses a received message as JobStatus
+func parseJobStatus(d amqp.Delivery) (JobStatus, error) {
+	var status JobStatus
 
 	// Required due to poo#114529
 	type IJobStatus struct {
@@ -167,8 +201,7 @@ func (sub *RabbitMQSubscription) ReceiveJobStatus() (JobStatus, error) {
 	}
 	// Try to unmarshall to json
 	var istatus IJobStatus
-	err = json.Unmarshal(d.Body, &istatus)
-	if err != nil {
+	if err := json.Unmarshal(d.Body, &istatus); err != nil {
 		return status, err
 	}
 	status.Arch = istatus.Arch
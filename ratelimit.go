@@ -0,0 +1,103 @@
+package gopenqa
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimit defines client-side limits for the requests to an openQA instance
+type RateLimit struct {
+	RequestsPerSecond float64 // Average number of requests per second. 0 means no limit
+	Burst             int     // Number of requests that can be started at once before the rate applies (minimum: 1)
+	MaxInFlight       int     // Maximum number of concurrent requests, if parallel requests are allowed. 0 means no limit
+}
+
+// limiter is a token bucket combined with a semaphore for the requests in flight
+type limiter struct {
+	mutex  sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64 // bucket size
+	tokens float64 // currently available tokens. Negative values are reservations of waiting requests
+	last   time.Time
+	slots  chan struct{} // semaphore for requests in flight, nil if unlimited
+}
+
+func newLimiter(limit RateLimit) *limiter {
+	l := &limiter{rate: limit.RequestsPerSecond, burst: float64(limit.Burst), last: time.Now()}
+	if l.burst < 1 {
+		l.burst = 1
+	}
+	l.tokens = l.burst
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// wait blocks until a request is allowed according to the request rate
+func (l *limiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return ctx.Err()
+	}
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	// Reserve a token and wait until it is available
+	l.tokens--
+	delay := time.Duration(0)
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mutex.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		// Give back the reserved token
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// acquire waits for the request rate and a free slot. Every successful acquire must be followed by a release
+func (l *limiter) acquire(ctx context.Context) error {
+	if err := l.wait(ctx); err != nil {
+		return err
+	}
+	if l.slots == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot acquired by acquire
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// limitedBody releases the limiter slot once the response body is closed
+type limitedBody struct {
+	io.ReadCloser
+	once    sync.Once
+	limiter *limiter
+}
+
+func (b *limitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.limiter.release)
+	return err
+}
@@ -0,0 +1,159 @@
+package gopenqa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy defines if and how failed requests are retried
+// Transient connection errors and responses with one of the StatusCodes are retried
+type RetryPolicy struct {
+	MaxAttempts    int           // Maximum number of attempts per request. Values <= 1 disable retries
+	InitialBackoff time.Duration // Delay before the first retry
+	MaxBackoff     time.Duration // Maximum delay between two attempts, except if the server requests a longer one via Retry-After (up to MaxRetryAfter)
+	Multiplier     float64       // Factor by which the delay grows after each attempt
+	Jitter         float64       // Random variation of each delay, as fraction of the delay (0-1)
+	StatusCodes    []int         // HTTP status codes that are retried
+	RetryPost      bool          // Retry also non-idempotent POST requests
+	MaxRetryAfter  time.Duration // Maximum delay the server can request via Retry-After. 0 limits it to MaxBackoff
+}
+
+// retrySleep waits between two attempts. Replaced by tests to not wait
+var retrySleep = sleepContext
+
+// DefaultRetryPolicy returns a retry policy suitable for busy openQA instances
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2.0,
+		Jitter:         0.2,
+		StatusCodes:    []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RetryPost:      false,
+		MaxRetryAfter:  5 * time.Minute,
+	}
+}
+
+// attempts returns the number of allowed attempts for the given method
+func (p *RetryPolicy) attempts(method string) int {
+	if p.MaxAttempts <= 1 {
+		return 1
+	}
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return p.MaxAttempts
+	case "POST":
+		if p.RetryPost {
+			return p.MaxAttempts
+		}
+	}
+	return 1
+}
+
+// retryable returns true, if the outcome of a request should be retried
+func (p *RetryPolicy) retryable(ctx context.Context, r *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return retryableError(err)
+	}
+	for _, code := range p.StatusCodes {
+		if r.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryableError returns true for transient connection errors like timeouts or resets
+// Permanent errors like certificate errors or unknown hosts are not retried
+func retryableError(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return true
+		}
+		// Temporary is deprecated, but still identifies e.g. temporary DNS failures
+		if temp, ok := netErr.(interface{ Temporary() bool }); ok && temp.Temporary() {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay after the given (failed) attempt
+func (p *RetryPolicy) backoff(attempt int, r *http.Response) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	ret := time.Duration(delay)
+	// The server knows best when to come back
+	if r != nil {
+		after := parseRetryAfter(r.Header.Get("Retry-After"))
+		// Don't let a misbehaving server or proxy block us for too long
+		limit := p.MaxRetryAfter
+		if limit <= 0 {
+			limit = p.MaxBackoff
+		}
+		if limit > 0 && after > limit {
+			after = limit
+		}
+		if after > ret {
+			ret = after
+		}
+	}
+	return ret
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either in seconds or a HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// sleepContext waits for the given duration or until the context is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
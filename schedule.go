@@ -0,0 +1,111 @@
+package gopenqa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+)
+
+/* Result of scheduling a product via isos post */
+type ScheduleResult struct {
+	Count              int               `json:"count"`                // Number of created jobs
+	IDs                []int64           `json:"ids"`                  // IDs of the created jobs
+	ScheduledProductID int64             `json:"scheduled_product_id"` // ID of the scheduled product
+	Failed             []ScheduleFailure `json:"failed"`               // Jobs that could not be created
+	GruTaskID          int64             `json:"gru_task_id"`          // Only set for asynchronous scheduling
+	MinionJobID        int64             `json:"minion_job_id"`        // Only set for asynchronous scheduling
+}
+
+/* Job that could not be created when scheduling a product */
+type ScheduleFailure struct {
+	JobName       string   `json:"job_name"`
+	ErrorMessages []string `json:"error_messages"`
+}
+
+func (f *ScheduleFailure) String() string {
+	return fmt.Sprintf("%s: %v", f.JobName, f.ErrorMessages)
+}
+
+// settingsValues converts the given settings to www-form-urlencoded parameters
+func settingsValues(settings map[string]string) url.Values {
+	params := url.Values{}
+	for k, v := range settings {
+		params.Add(k, v)
+	}
+	return params
+}
+
+/* Schedule a product (isos post). The settings must contain at least DISTRI, VERSION, FLAVOR and ARCH
+ * Jobs that could not be created are reported in ScheduleResult.Failed
+ */
+func (i *Instance) PostISO(settings map[string]string) (ScheduleResult, error) {
+	return i.PostISOContext(context.Background(), settings)
+}
+
+func (i *Instance) PostISOContext(ctx context.Context, settings map[string]string) (ScheduleResult, error) {
+	var result ScheduleResult
+	if i.apikey == "" || i.apisecret == "" {
+		return result, fmt.Errorf("API key or secret not set")
+	}
+	rurl := fmt.Sprintf("%s/api/v1/isos", i.URL)
+	buf, err := i.post(ctx, rurl, []byte(settingsValues(settings).Encode()))
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(buf, &result)
+	return result, err
+}
+
+/* Create a single job (jobs post) with the given settings and return the IDs of the created jobs */
+func (i *Instance) PostJob(settings map[string]string) ([]int64, error) {
+	return i.PostJobContext(context.Background(), settings)
+}
+
+func (i *Instance) PostJobContext(ctx context.Context, settings map[string]string) ([]int64, error) {
+	ids := make([]int64, 0)
+	if i.apikey == "" || i.apisecret == "" {
+		return ids, fmt.Errorf("API key or secret not set")
+	}
+	rurl := fmt.Sprintf("%s/api/v1/jobs", i.URL)
+	buf, err := i.post(ctx, rurl, []byte(settingsValues(settings).Encode()))
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	if err != nil {
+		return ids, err
+	}
+	// A single job is returned as {"id":1}, multiple jobs as {"ids":[1,2]} or {"ids":{"name":1}}
+	var result struct {
+		ID  int64           `json:"id"`
+		IDs json.RawMessage `json:"ids"`
+	}
+	if err := json.Unmarshal(buf, &result); err != nil {
+		return ids, err
+	}
+	if len(result.IDs) > 0 {
+		var list []int64
+		var dict map[string]int64
+		if err := json.Unmarshal(result.IDs, &list); err == nil {
+			ids = append(ids, list...)
+		} else if err := json.Unmarshal(result.IDs, &dict); err == nil {
+			for _, id := range dict {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+		} else {
+			return ids, ErrInvalidResponse
+		}
+	} else if result.ID > 0 {
+		ids = append(ids, result.ID)
+	}
+	if len(ids) == 0 {
+		return ids, ErrInvalidResponse
+	}
+	return ids, nil
+}
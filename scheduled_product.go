@@ -0,0 +1,111 @@
+package gopenqa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Status of a scheduled product
+const (
+	ScheduledProductAdded      = "added"
+	ScheduledProductScheduling = "scheduling"
+	ScheduledProductScheduled  = "scheduled"
+	ScheduledProductCancelling = "cancelling"
+	ScheduledProductCancelled  = "cancelled"
+)
+
+/* Scheduled product, i.e. a product that has been scheduled via isos post */
+type ScheduledProduct struct {
+	ID          int64                   `json:"id"`
+	Distri      string                  `json:"distri"`
+	Version     string                  `json:"version"`
+	Flavor      string                  `json:"flavor"`
+	Arch        string                  `json:"arch"`
+	Build       string                  `json:"build"`
+	ISO         string                  `json:"iso"`
+	Status      string                  `json:"status"` // added, scheduling, scheduled, cancelling or cancelled
+	Settings    map[string]interface{}  `json:"settings"`
+	Results     ScheduledProductResults `json:"results"`
+	UserID      int64                   `json:"user_id"`
+	GruTaskID   int64                   `json:"gru_task_id"`
+	MinionJobID int64                   `json:"minion_job_id"`
+	JobIDs      []int64                 `json:"job_ids"` // IDs of all jobs created by this product
+	Tcreated    string                  `json:"t_created"`
+	Tupdated    string                  `json:"t_updated"`
+}
+
+/* Results of scheduling a product */
+type ScheduledProductResults struct {
+	SuccessfulJobIDs []int64           `json:"successful_job_ids"`
+	FailedJobInfo    []ScheduleFailure `json:"failed_job_info"` // Jobs that could not be created
+	Error            string            `json:"error"`           // Error that prevented scheduling, if any
+}
+
+/* IsFinished returns true if the scheduling is done, i.e. the product has been scheduled or cancelled */
+func (p *ScheduledProduct) IsFinished() bool {
+	return p.Status == ScheduledProductScheduled || p.Status == ScheduledProductCancelled
+}
+
+/* Get a scheduled product including the IDs of its jobs */
+func (i *Instance) GetScheduledProduct(id int64) (ScheduledProduct, error) {
+	return i.GetScheduledProductContext(context.Background(), id)
+}
+
+func (i *Instance) GetScheduledProductContext(ctx context.Context, id int64) (ScheduledProduct, error) {
+	var product ScheduledProduct
+	url := fmt.Sprintf("%s/api/v1/isos/%d?include_job_ids=1", i.URL, id)
+	resp, err := i.get(ctx, url, nil)
+	if err != nil {
+		return product, err
+	}
+	err = json.Unmarshal(resp, &product)
+	return product, err
+}
+
+/* Get all jobs that have been created by the given scheduled product */
+func (i *Instance) GetScheduledProductJobs(id int64) ([]Job, error) {
+	return i.GetScheduledProductJobsContext(context.Background(), id)
+}
+
+func (i *Instance) GetScheduledProductJobsContext(ctx context.Context, id int64) ([]Job, error) {
+	product, err := i.GetScheduledProductContext(ctx, id)
+	if err != nil {
+		return make([]Job, 0), err
+	}
+	return i.GetJobsContext(ctx, product.JobIDs)
+}
+
+/* Get the jobs of the given scheduled product that failed, see Job.IsFailed */
+func (i *Instance) GetScheduledProductFailedJobs(id int64) ([]Job, error) {
+	return i.GetScheduledProductFailedJobsContext(context.Background(), id)
+}
+
+func (i *Instance) GetScheduledProductFailedJobsContext(ctx context.Context, id int64) ([]Job, error) {
+	ret := make([]Job, 0)
+	jobs, err := i.GetScheduledProductJobsContext(ctx, id)
+	if err != nil {
+		return ret, err
+	}
+	for _, job := range jobs {
+		if job.IsFailed() {
+			ret = append(ret, job)
+		}
+	}
+	return ret, nil
+}
+
+/* Cancel the given scheduled product. openQA stops scheduling it and cancels all of its jobs */
+func (i *Instance) CancelScheduledProduct(id int64) error {
+	return i.CancelScheduledProductContext(context.Background(), id)
+}
+
+func (i *Instance) CancelScheduledProductContext(ctx context.Context, id int64) error {
+	rurl := fmt.Sprintf("%s/api/v1/isos/%d/cancel", i.URL, id)
+	buf, err := i.post(ctx, rurl, nil)
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	return err
+}
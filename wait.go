@@ -0,0 +1,127 @@
+package gopenqa
+
+import (
+	"context"
+	"time"
+)
+
+/* Options for waiting for jobs to finish */
+type WaitOptions struct {
+	PollInterval    time.Duration // Initial interval between two polls (default: 10 seconds)
+	MaxPollInterval time.Duration // The poll interval doubles while nothing changes up to this value (default: 5 minutes)
+	Follow          bool          // Follow cloned jobs, i.e. wait for the restarted jobs instead
+	// If set, job updates received via this subscription trigger an immediate poll of the affected jobs
+	// Messages are consumed only while the waiter waits between two polls, so the subscription must not be used concurrently
+	// It can be used again once the waiter is done
+	Subscription *RabbitMQSubscription
+}
+
+func (opts *WaitOptions) applyDefaults() {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 10 * time.Second
+	}
+	if opts.MaxPollInterval <= 0 {
+		opts.MaxPollInterval = 5 * time.Minute
+	}
+	if opts.MaxPollInterval < opts.PollInterval {
+		opts.MaxPollInterval = opts.PollInterval
+	}
+}
+
+/* Block until the given job reaches a final state and return this state
+ * If opts.Follow is set, the state of the last clone is returned. JobState.ID is then the ID of the clone
+ */
+func (i *Instance) WaitForJob(id int64, opts WaitOptions) (JobState, error) {
+	return i.WaitForJobContext(context.Background(), id, opts)
+}
+
+func (i *Instance) WaitForJobContext(ctx context.Context, id int64, opts WaitOptions) (JobState, error) {
+	states, err := i.WaitForJobsContext(ctx, []int64{id}, opts)
+	return states[id], err
+}
+
+/* Block until all given jobs reach a final state. Returns the final states by the given job IDs
+ * If opts.Follow is set, the state of the last clone is returned. JobState.ID is then the ID of the clone
+ */
+func (i *Instance) WaitForJobs(ids []int64, opts WaitOptions) (map[int64]JobState, error) {
+	return i.WaitForJobsContext(context.Background(), ids, opts)
+}
+
+func (i *Instance) WaitForJobsContext(ctx context.Context, ids []int64, opts WaitOptions) (map[int64]JobState, error) {
+	opts.applyDefaults()
+	states := make(map[int64]JobState, 0)
+	pending := make(map[int64]int64, 0) // Current job ID (possibly a clone) by the original job ID
+	for _, id := range ids {
+		pending[id] = id
+	}
+
+	interval := opts.PollInterval
+	for {
+		changed := false
+		for orig, id := range pending {
+			state, err := i.GetJobStateContext(ctx, id)
+			if err != nil {
+				return states, err
+			}
+			if !state.IsFinal() {
+				continue
+			}
+			if opts.Follow {
+				job, err := i.GetJobContext(ctx, id)
+				if err != nil {
+					return states, err
+				}
+				if job.IsCloned() {
+					pending[orig] = job.CloneID
+					changed = true
+					continue
+				}
+			}
+			state.ID = id
+			states[orig] = state
+			delete(pending, orig)
+			changed = true
+		}
+		if len(pending) == 0 {
+			return states, nil
+		}
+
+		// Poll more often if things are moving
+		if changed {
+			interval = opts.PollInterval
+		} else if interval *= 2; interval > opts.MaxPollInterval {
+			interval = opts.MaxPollInterval
+		}
+		if err := waitForUpdate(ctx, interval, opts.Subscription, pending); err != nil {
+			return states, err
+		}
+	}
+}
+
+// waitForUpdate waits for the given interval or until an update for one of the pending jobs is received via the subscription, if given
+// Messages are only received while waiting, so that the subscription is not consumed once the waiter is done
+func waitForUpdate(ctx context.Context, interval time.Duration, sub *RabbitMQSubscription, pending map[int64]int64) error {
+	if sub == nil {
+		return sleepContext(ctx, interval)
+	}
+	wctx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+	for {
+		d, err := sub.ReceiveContext(wctx)
+		if err != nil {
+			// Interval elapsed or subscription closed. In the latter case, waiting continues with polling only
+			<-wctx.Done()
+			return ctx.Err()
+		}
+		status, err := parseJobStatus(d)
+		if err != nil {
+			// Not a job status update, e.g. a comment on the same exchange
+			continue
+		}
+		for _, id := range pending {
+			if id == status.ID {
+				return nil
+			}
+		}
+	}
+}
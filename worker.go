@@ -1,5 +1,12 @@
 package gopenqa
 
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
 /* Worker instance */
 type Worker struct {
 	Alive      int               `json:"alive"`
@@ -8,7 +15,149 @@ type Worker struct {
 	Host       string            `json:"host"`
 	ID         int               `json:"id"`
 	Instance   int               `json:"instance"`
+	JobID      int64             `json:"jobid"` // ID of the current job, 0 if the worker is not running a job
 	Status     string            `json:"status"`
 	Websocket  int               `json:"websocket"`
 	Properties map[string]string `json:"properties"` // Worker properties as returned by openQA
 }
+
+/* Name returns the worker name as used by openQA, i.e. host:instance */
+func (w *Worker) Name() string {
+	return fmt.Sprintf("%s:%d", w.Host, w.Instance)
+}
+
+/* Classes returns the worker classes from the WORKER_CLASS property */
+func (w *Worker) Classes() []string {
+	ret := make([]string, 0)
+	for _, class := range strings.Split(w.Properties["WORKER_CLASS"], ",") {
+		if class = strings.TrimSpace(class); class != "" {
+			ret = append(ret, class)
+		}
+	}
+	return ret
+}
+
+/* HasClass returns true if the worker has the given worker class */
+func (w *Worker) HasClass(class string) bool {
+	for _, c := range w.Classes() {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+/* JobToken returns the JOBTOKEN property of the worker */
+func (w *Worker) JobToken() string {
+	return w.Properties["JOBTOKEN"]
+}
+
+/* IsAlive returns true if the worker is alive */
+func (w *Worker) IsAlive() bool {
+	return w.Alive != 0
+}
+
+/* IsOffline returns true if the worker is offline, which openQA reports as status "dead". Only offline workers can be deleted */
+func (w *Worker) IsOffline() bool {
+	return w.Status == "dead"
+}
+
+/* Filter for workers, returns true if the worker matches */
+type WorkerFilter func(w *Worker) bool
+
+/* FilterWorkers returns the workers that match all given filters */
+func FilterWorkers(workers []Worker, filters ...WorkerFilter) []Worker {
+	ret := make([]Worker, 0)
+	for _, worker := range workers {
+		matches := true
+		for _, filter := range filters {
+			if !filter(&worker) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			ret = append(ret, worker)
+		}
+	}
+	return ret
+}
+
+/* WorkerByHost matches workers on the given host */
+func WorkerByHost(host string) WorkerFilter {
+	return func(w *Worker) bool { return w.Host == host }
+}
+
+/* WorkerByClass matches workers with the given worker class */
+func WorkerByClass(class string) WorkerFilter {
+	return func(w *Worker) bool { return w.HasClass(class) }
+}
+
+/* WorkerByStatus matches workers with any of the given status, e.g. "idle", "running", "broken" or "dead" */
+func WorkerByStatus(status ...string) WorkerFilter {
+	return func(w *Worker) bool {
+		for _, s := range status {
+			if w.Status == s {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+/* WorkerByAlive matches workers that are alive or not alive */
+func WorkerByAlive(alive bool) WorkerFilter {
+	return func(w *Worker) bool { return w.IsAlive() == alive }
+}
+
+/* Get a single worker */
+func (i *Instance) GetWorker(id int) (Worker, error) {
+	return i.GetWorkerContext(context.Background(), id)
+}
+
+func (i *Instance) GetWorkerContext(ctx context.Context, id int) (Worker, error) {
+	url := fmt.Sprintf("%s/api/v1/workers/%d", i.URL, id)
+	return i.fetchWorker(ctx, url)
+}
+
+/* Delete the given worker. openQA only allows deleting offline workers */
+func (i *Instance) DeleteWorker(id int) error {
+	return i.DeleteWorkerContext(context.Background(), id)
+}
+
+func (i *Instance) DeleteWorkerContext(ctx context.Context, id int) error {
+	if i.apikey == "" || i.apisecret == "" {
+		return fmt.Errorf("API key or secret not set")
+	}
+
+	url := fmt.Sprintf("%s/api/v1/workers/%d", i.URL, id)
+	buf, err := i.delete(ctx, url, nil)
+	if i.verbose {
+		fmt.Fprintf(os.Stderr, "%s\n", string(buf))
+	}
+	return err
+}
+
+/* Delete all offline workers that match the given filters. Returns the deleted workers
+ */
+func (i *Instance) DeleteOfflineWorkers(filters ...WorkerFilter) ([]Worker, error) {
+	return i.DeleteOfflineWorkersContext(context.Background(), filters...)
+}
+
+func (i *Instance) DeleteOfflineWorkersContext(ctx context.Context, filters ...WorkerFilter) ([]Worker, error) {
+	deleted := make([]Worker, 0)
+	workers, err := i.GetWorkersContext(ctx)
+	if err != nil {
+		return deleted, err
+	}
+	// Copy the filters, so that the slice of the caller is never modified
+	offline := append(make([]WorkerFilter, 0, len(filters)+1), filters...)
+	offline = append(offline, func(w *Worker) bool { return w.IsOffline() })
+	for _, worker := range FilterWorkers(workers, offline...) {
+		if err := i.DeleteWorkerContext(ctx, worker.ID); err != nil {
+			return deleted, fmt.Errorf("worker %s: %w", worker.Name(), err)
+		}
+		deleted = append(deleted, worker)
+	}
+	return deleted, nil
+}